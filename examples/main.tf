@@ -7,7 +7,7 @@ terraform {
 }
 
 provider "swarm" {
-  endpoint = "unix:///var/run/docker.sock"
+  host = "unix:///var/run/docker.sock"
 }
 
 resource "swarm_service" "example" {
@@ -15,9 +15,15 @@ resource "swarm_service" "example" {
   image    = "nginx:latest"
   replicas = 3
 
-  configurable_attribute = "example"
+  node = {
+    host = "unix:///var/run/docker.sock"
+  }
 }
 
 data "swarm_service" "example" {
-  id = swarm_service.example.id
-}
\ No newline at end of file
+  name = swarm_service.example.name
+
+  node = {
+    host = "unix:///var/run/docker.sock"
+  }
+}
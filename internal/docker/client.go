@@ -7,11 +7,13 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"os"
 	"path/filepath"
 	"runtime"
 	"time"
 
 	"github.com/docker/cli/cli/connhelper"
+	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
 )
 
@@ -24,14 +26,32 @@ type Config struct {
 	Cert     string
 	Key      string
 	CertPath string
+	// KeyPath and CaPath override the key.pem and ca.pem files of CertPath
+	KeyPath string
+	CaPath  string
+	// APIVersion pins the Docker API version instead of negotiating it
+	APIVersion string
+	// Context is the name of a Docker CLI context providing the host and
+	// TLS material that are not set explicitly
+	Context string
+	// SkipTLSVerify disables the verification of the daemon certificate
+	SkipTLSVerify bool
+	// SSH connects to ssh:// hosts in-process instead of with the ssh binary
+	SSH *SSHConfig
+
+	// resolved is set on the configs returned by Resolve
+	resolved bool
 }
 
-// buildHTTPClientFromBytes builds the http client from bytes (content of the files)
-func buildHTTPClientFromBytes(caPEMCert, certPEMBlock, keyPEMBlock []byte) (*http.Client, error) {
+// buildHTTPClientFromBytes builds the http client from bytes (content of the
+// files). Without CA certificate, the daemon is verified against the system
+// roots.
+func buildHTTPClientFromBytes(caPEMCert, certPEMBlock, keyPEMBlock []byte, skipVerify bool) (*http.Client, error) {
 	tlsConfig := &tls.Config{
-		MinVersion: tls.VersionTLS12, // Fix gosec G402: Set minimum TLS version
+		MinVersion:         tls.VersionTLS12, // Fix gosec G402: Set minimum TLS version
+		InsecureSkipVerify: skipVerify,       // #nosec G402 -- set by the user
 	}
-	if certPEMBlock != nil && keyPEMBlock != nil {
+	if len(certPEMBlock) > 0 && len(keyPEMBlock) > 0 {
 		tlsCert, err := tls.X509KeyPair(certPEMBlock, keyPEMBlock)
 		if err != nil {
 			return nil, err
@@ -39,9 +59,7 @@ func buildHTTPClientFromBytes(caPEMCert, certPEMBlock, keyPEMBlock []byte) (*htt
 		tlsConfig.Certificates = []tls.Certificate{tlsCert}
 	}
 
-	if len(caPEMCert) == 0 {
-		tlsConfig.InsecureSkipVerify = true
-	} else {
+	if len(caPEMCert) > 0 {
 		caPool := x509.NewCertPool()
 		if !caPool.AppendCertsFromPEM(caPEMCert) {
 			return nil, errors.New("could not add RootCA pem")
@@ -49,22 +67,14 @@ func buildHTTPClientFromBytes(caPEMCert, certPEMBlock, keyPEMBlock []byte) (*htt
 		tlsConfig.RootCAs = caPool
 	}
 
-	tr := defaultTransport()
+	tr := defaultPooledTransport()
 	tr.TLSClientConfig = tlsConfig
 	return &http.Client{Transport: tr}, nil
 }
 
-// defaultTransport returns a new http.Transport with similar default values to
-// http.DefaultTransport, but with idle connections and keepalives disabled.
-func defaultTransport() *http.Transport {
-	transport := defaultPooledTransport()
-	transport.DisableKeepAlives = true
-	transport.MaxIdleConnsPerHost = -1
-	return transport
-}
-
 // defaultPooledTransport returns a new http.Transport with similar default
-// values to http.DefaultTransport.
+// values to http.DefaultTransport. Connections are kept alive, so that the
+// clients shared by the Pool reuse them between requests.
 func defaultPooledTransport() *http.Transport {
 	transport := &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
@@ -81,8 +91,19 @@ func defaultPooledTransport() *http.Transport {
 	return transport
 }
 
-// NewClient returns a new Docker client.
+// NewClient returns a new Docker client, for the connection settings
+// completed by Resolve.
 func (c *Config) NewClient() (*client.Client, error) {
+	resolved, err := c.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	c = &resolved
+
+	if c.SSH != nil {
+		return c.newSSHClient()
+	}
+
 	if c.Cert != "" || c.Key != "" {
 		if c.Cert == "" || c.Key == "" {
 			return nil, fmt.Errorf("cert_material, and key_material must be specified")
@@ -91,8 +112,10 @@ func (c *Config) NewClient() (*client.Client, error) {
 		if c.CertPath != "" {
 			return nil, fmt.Errorf("cert_path '%s' must not be specified", c.CertPath)
 		}
+	}
 
-		httpClient, err := buildHTTPClientFromBytes([]byte(c.Ca), []byte(c.Cert), []byte(c.Key))
+	if c.Ca != "" || c.Cert != "" || c.Key != "" || (c.SkipTLSVerify && !c.hasTLS()) {
+		httpClient, err := buildHTTPClientFromBytes([]byte(c.Ca), []byte(c.Cert), []byte(c.Key), c.SkipTLSVerify)
 		if err != nil {
 			return nil, err
 		}
@@ -102,19 +125,28 @@ func (c *Config) NewClient() (*client.Client, error) {
 		return client.NewClientWithOpts(
 			client.WithHTTPClient(httpClient),
 			client.WithHost(c.Host),
-			client.WithAPIVersionNegotiation(),
+			c.versionOpt(),
 		)
 	}
 
-	if c.CertPath != "" {
+	if c.CertPath != "" || c.KeyPath != "" || c.CaPath != "" {
 		// If there is cert information, load it and use it.
-		ca := filepath.Join(c.CertPath, "ca.pem")
-		cert := filepath.Join(c.CertPath, "cert.pem")
-		key := filepath.Join(c.CertPath, "key.pem")
+		ca, cert, key, err := c.tlsFiles()
+		if err != nil {
+			return nil, err
+		}
+		caPEMCert, certPEMBlock, keyPEMBlock, err := readTLSFiles(ca, cert, key)
+		if err != nil {
+			return nil, err
+		}
+		httpClient, err := buildHTTPClientFromBytes(caPEMCert, certPEMBlock, keyPEMBlock, c.SkipTLSVerify)
+		if err != nil {
+			return nil, err
+		}
 		return client.NewClientWithOpts(
+			client.WithHTTPClient(httpClient),
 			client.WithHost(c.Host),
-			client.WithTLSClientConfig(ca, cert, key),
-			client.WithAPIVersionNegotiation(),
+			c.versionOpt(),
 		)
 	}
 
@@ -127,13 +159,104 @@ func (c *Config) NewClient() (*client.Client, error) {
 		return client.NewClientWithOpts(
 			client.WithHost(helper.Host),
 			client.WithDialContext(helper.Dialer),
-			client.WithAPIVersionNegotiation(),
+			c.versionOpt(),
 		)
 	}
 
 	// If there is no ssh://, then just return the direct client
 	return client.NewClientWithOpts(
 		client.WithHost(c.Host),
-		client.WithAPIVersionNegotiation(),
+		c.versionOpt(),
+	)
+}
+
+// newSSHClient returns a client talking to the Docker socket of an ssh://
+// host through the in-process SSH transport.
+func (c *Config) newSSHClient() (*client.Client, error) {
+	if !isSSHHost(c.Host) {
+		return nil, fmt.Errorf("ssh must only be specified for ssh:// hosts, got '%s'", c.Host)
+	}
+	if len(c.SSHOpts) > 0 {
+		return nil, fmt.Errorf("ssh_opts must not be specified with ssh")
+	}
+
+	dialer, err := newSSHDialer(c.Host, *c.SSH)
+	if err != nil {
+		return nil, err
+	}
+	// The host only names the daemon in the requests, the dialer reaches it
+	return client.NewClientWithOpts(
+		client.WithHost("http://docker.example.com"),
+		client.WithDialContext(dialer.DialContext),
+		c.versionOpt(),
 	)
 }
+
+// versionOpt pins the configured API version, or negotiates it with the
+// daemon when none is configured.
+func (c *Config) versionOpt() client.Opt {
+	if c.APIVersion != "" {
+		return client.WithVersion(c.APIVersion)
+	}
+	return client.WithAPIVersionNegotiation()
+}
+
+// tlsFiles returns the CA, certificate and key files, the ones of CertPath
+// being overridden by CaPath and KeyPath.
+func (c *Config) tlsFiles() (string, string, string, error) {
+	if c.KeyPath != "" && c.CertPath == "" {
+		return "", "", "", fmt.Errorf("cert_path must be specified with key_path '%s'", c.KeyPath)
+	}
+	var ca, cert, key string
+	if c.CertPath != "" {
+		ca = filepath.Join(c.CertPath, "ca.pem")
+		cert = filepath.Join(c.CertPath, "cert.pem")
+		key = filepath.Join(c.CertPath, "key.pem")
+	}
+	if c.CaPath != "" {
+		ca = c.CaPath
+	}
+	if c.KeyPath != "" {
+		key = c.KeyPath
+	}
+	return ca, cert, key, nil
+}
+
+// readTLSFiles reads the CA, certificate and key files, skipping the ones
+// that are not set.
+func readTLSFiles(ca, cert, key string) ([]byte, []byte, []byte, error) {
+	var contents [3][]byte
+	for i, file := range []string{ca, cert, key} {
+		if file == "" {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("could not read TLS file: %w", err)
+		}
+		contents[i] = content
+	}
+	return contents[0], contents[1], contents[2], nil
+}
+
+// EncodeRegistryAuth encodes the registry credentials given as a map with the
+// address, username, password and identity_token keys, for the
+// X-Registry-Auth header of the service requests.
+func EncodeRegistryAuth(auth map[string]string) (string, error) {
+	authConfig := registry.AuthConfig{}
+	for key, value := range auth {
+		switch key {
+		case "address":
+			authConfig.ServerAddress = value
+		case "username":
+			authConfig.Username = value
+		case "password":
+			authConfig.Password = value
+		case "identity_token":
+			authConfig.IdentityToken = value
+		default:
+			return "", fmt.Errorf("unsupported registry_auth key '%s', expected address, username, password or identity_token", key)
+		}
+	}
+	return registry.EncodeAuthConfig(authConfig)
+}
@@ -52,6 +52,22 @@ func TestConfig_NewClient(t *testing.T) {
 			},
 			wantErr: true, // Should fail because cert content is not valid PEM
 		},
+		{
+			name: "pinned api version",
+			config: Config{
+				Host:       "tcp://localhost:2376",
+				APIVersion: "1.41",
+			},
+			wantErr: false,
+		},
+		{
+			name: "key path without cert path",
+			config: Config{
+				Host:    "tcp://localhost:2376",
+				KeyPath: "/path/to/key.pem",
+			},
+			wantErr: true, // Should fail because the certificate is unknown
+		},
 	}
 
 	for _, tt := range tests {
@@ -71,6 +87,40 @@ func TestConfig_NewClient(t *testing.T) {
 	}
 }
 
+func TestConfig_NewClientAPIVersion(t *testing.T) {
+	config := Config{Host: "tcp://localhost:2376", APIVersion: "1.41"}
+
+	client, err := config.NewClient()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1.41", client.ClientVersion())
+	client.Close()
+}
+
+func TestConfig_TLSFiles(t *testing.T) {
+	config := Config{CertPath: "/certs", KeyPath: "/keys/client.pem"}
+
+	ca, cert, key, err := config.tlsFiles()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/certs/ca.pem", ca)
+	assert.Equal(t, "/certs/cert.pem", cert)
+	assert.Equal(t, "/keys/client.pem", key)
+}
+
+func TestEncodeRegistryAuth(t *testing.T) {
+	encoded, err := EncodeRegistryAuth(map[string]string{
+		"address":  "registry.example.com",
+		"username": "deploy",
+		"password": "secret",
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, encoded)
+
+	_, err = EncodeRegistryAuth(map[string]string{"user": "deploy"})
+	assert.Error(t, err)
+}
+
 func TestExtractConfig(t *testing.T) {
 	node := TfNode{
 		Host:         types.StringValue("ssh://user@host"),
@@ -89,5 +139,6 @@ func TestExtractConfig(t *testing.T) {
 	assert.Equal(t, "key-content", config.Key)
 	assert.Equal(t, "ca-content", config.Ca)
 	assert.Equal(t, "/path/to/certs", config.CertPath)
+	assert.Equal(t, "remote", config.Context)
 	assert.Empty(t, config.SSHOpts) // Should be empty for null list
 }
\ No newline at end of file
@@ -0,0 +1,99 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	clicontext "github.com/docker/cli/cli/context"
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/docker/docker/client"
+)
+
+// DefaultContextName is the name of the implicit context of the Docker CLI,
+// which is not stored and talks to the default daemon host.
+const DefaultContextName = "default"
+
+// contextEndpoint is the docker endpoint of a context of the Docker CLI
+// context store.
+type contextEndpoint struct {
+	Host          string
+	SkipTLSVerify bool
+	// TLSData is nil when the context has no TLS material
+	TLSData *clicontext.TLSData
+}
+
+// configDir returns the configuration directory of the Docker CLI,
+// DOCKER_CONFIG or ~/.docker.
+func configDir() string {
+	dir := os.Getenv(EnvOverrideConfigDir)
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".docker")
+	}
+	return dir
+}
+
+// contextStoreDir returns the directory of the context store of the Docker
+// CLI.
+func contextStoreDir() string {
+	return filepath.Join(configDir(), "contexts")
+}
+
+// currentContext returns the context selected with DOCKER_CONTEXT, or with
+// "docker context use" in the config.json of the Docker CLI. It is empty when
+// no context is selected.
+func currentContext() (string, error) {
+	if name := os.Getenv(EnvOverrideContext); name != "" {
+		return name, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(configDir(), "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not read docker config: %w", err)
+	}
+	var cliConfig struct {
+		CurrentContext string `json:"currentContext"`
+	}
+	if err := json.Unmarshal(content, &cliConfig); err != nil {
+		return "", fmt.Errorf("could not parse docker config: %w", err)
+	}
+	return cliConfig.CurrentContext, nil
+}
+
+// loadContext reads the docker endpoint of the named context from the
+// context store of the Docker CLI, with its TLS material.
+func loadContext(name string) (*contextEndpoint, error) {
+	if name == DefaultContextName {
+		return &contextEndpoint{Host: client.DefaultDockerHost}, nil
+	}
+
+	contextStore := store.New(contextStoreDir(), store.NewConfig(
+		func() any { return &map[string]any{} },
+		store.EndpointTypeGetter(docker.DockerEndpoint, func() any { return &docker.EndpointMeta{} }),
+	))
+	metadata, err := contextStore.GetMetadata(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not load docker context '%s': %w", name, err)
+	}
+	endpointMeta, err := docker.EndpointFromContext(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("could not load docker context '%s': %w", name, err)
+	}
+	tlsData, err := clicontext.LoadTLSData(contextStore, name, docker.DockerEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("could not load docker context '%s': %w", name, err)
+	}
+
+	return &contextEndpoint{
+		Host:          endpointMeta.Host,
+		SkipTLSVerify: endpointMeta.SkipTLSVerify,
+		TLSData:       tlsData,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/cli/cli/context/store"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeContext stores a context with a docker endpoint in the context store
+// of a temporary DOCKER_CONFIG.
+func writeContext(t *testing.T, name string, endpoint docker.EndpointMeta, tlsFiles map[string][]byte) {
+	t.Helper()
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	contextStore := store.New(contextStoreDir(), store.NewConfig(
+		func() any { return &map[string]any{} },
+		store.EndpointTypeGetter(docker.DockerEndpoint, func() any { return &docker.EndpointMeta{} }),
+	))
+	assert.NoError(t, contextStore.CreateOrUpdate(store.Metadata{
+		Name:      name,
+		Metadata:  map[string]any{},
+		Endpoints: map[string]any{docker.DockerEndpoint: endpoint},
+	}))
+	if tlsFiles != nil {
+		assert.NoError(t, contextStore.ResetEndpointTLSMaterial(name, docker.DockerEndpoint, &store.EndpointTLSData{Files: tlsFiles}))
+	}
+}
+
+func TestLoadContext(t *testing.T) {
+	writeContext(t, "prod", docker.EndpointMeta{Host: "tcp://192.168.1.100:2376"}, map[string][]byte{
+		"ca.pem":   []byte("ca-content"),
+		"cert.pem": []byte("cert-content"),
+		"key.pem":  []byte("key-content"),
+	})
+
+	endpoint, err := loadContext("prod")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://192.168.1.100:2376", endpoint.Host)
+	assert.NotNil(t, endpoint.TLSData)
+	assert.Equal(t, []byte("ca-content"), endpoint.TLSData.CA)
+	assert.Equal(t, []byte("cert-content"), endpoint.TLSData.Cert)
+	assert.Equal(t, []byte("key-content"), endpoint.TLSData.Key)
+
+	// The material is not valid PEM
+	config := Config{Context: "prod"}
+	_, err = config.NewClient()
+	assert.Error(t, err)
+
+	_, err = loadContext("staging")
+	assert.Error(t, err)
+}
+
+func TestLoadContext_Default(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	endpoint, err := loadContext(DefaultContextName)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, endpoint.Host)
+	assert.Nil(t, endpoint.TLSData)
+}
+
+func TestConfig_NewClientContext(t *testing.T) {
+	writeContext(t, "prod", docker.EndpointMeta{Host: "tcp://192.168.1.100:2376", SkipTLSVerify: true}, nil)
+
+	config := Config{Context: "prod"}
+	client, err := config.NewClient()
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://192.168.1.100:2376", client.DaemonHost())
+	client.Close()
+
+	// An explicit host overrides the one of the context
+	config = Config{Host: "tcp://192.168.1.101:2376", Context: "prod"}
+	client, err = config.NewClient()
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://192.168.1.101:2376", client.DaemonHost())
+	client.Close()
+
+	config = Config{Context: "staging"}
+	_, err = config.NewClient()
+	assert.Error(t, err)
+}
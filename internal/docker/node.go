@@ -1,7 +1,12 @@
 package docker
 
 import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -14,18 +19,32 @@ type TfNode struct {
 	KeyMaterial  tfTypes.String `tfsdk:"key_material"`
 	CaMaterial   tfTypes.String `tfsdk:"ca_material"`
 	CertPath     tfTypes.String `tfsdk:"cert_path"`
+	SSH          *TfSSH         `tfsdk:"ssh"`
+}
+
+// TfSSH is the ssh block of a node connection, selecting the in-process SSH
+// transport for ssh:// hosts.
+type TfSSH struct {
+	PrivateKey         tfTypes.String `tfsdk:"private_key"`
+	Passphrase         tfTypes.String `tfsdk:"private_key_passphrase"`
+	AgentSocket        tfTypes.String `tfsdk:"agent_socket"`
+	KnownHosts         tfTypes.String `tfsdk:"known_hosts"`
+	HostKeyFingerprint tfTypes.String `tfsdk:"host_key_fingerprint"`
+	User               tfTypes.String `tfsdk:"user"`
+	Port               tfTypes.Int64  `tfsdk:"port"`
+	ConnectTimeout     tfTypes.String `tfsdk:"connect_timeout"`
 }
 
 var NodeSchema = schema.SingleNestedAttribute{
-	Description: "Docker connection configuration for this node.",
-	Required:    true,
+	Description: "Docker connection configuration for this node. Defaults to the connection of the provider.",
+	Optional:    true,
 	Attributes: map[string]schema.Attribute{
 		"host": schema.StringAttribute{
-			Description: "Docker daemon host for this node",
-			Required:    true,
+			Description: "Docker daemon host for this node. Overrides the host of context",
+			Optional:    true,
 		},
 		"context": schema.StringAttribute{
-			Description: "Docker context to use for this node",
+			Description: "Name of a Docker CLI context providing the host and TLS material of this node, read from the context store under DOCKER_CONFIG or ~/.docker",
 			Optional:    true,
 		},
 		"ssh_opts": schema.ListAttribute{
@@ -49,9 +68,121 @@ var NodeSchema = schema.SingleNestedAttribute{
 			Description: "Path to directory with Docker TLS config",
 			Optional:    true,
 		},
+		"ssh": schema.SingleNestedAttribute{
+			Description: "In-process SSH transport for ssh:// hosts, used instead of the ssh binary and ssh_opts",
+			Optional:    true,
+			Attributes: map[string]schema.Attribute{
+				"private_key": schema.StringAttribute{
+					Description: "PEM-encoded private key authenticating the user",
+					Optional:    true,
+					Sensitive:   true,
+				},
+				"private_key_passphrase": schema.StringAttribute{
+					Description: "Passphrase of private_key",
+					Optional:    true,
+					Sensitive:   true,
+				},
+				"agent_socket": schema.StringAttribute{
+					Description: "Socket of the SSH agent authenticating the user. Defaults to SSH_AUTH_SOCK when private_key is not set",
+					Optional:    true,
+				},
+				"known_hosts": schema.StringAttribute{
+					Description: "Content of a known_hosts file verifying the host key",
+					Optional:    true,
+				},
+				"host_key_fingerprint": schema.StringAttribute{
+					Description: "SHA256 fingerprint of the host key, as printed by ssh-keygen -l (e.g. SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8)",
+					Optional:    true,
+				},
+				"user": schema.StringAttribute{
+					Description: "SSH user. Defaults to the user of host, then to the current user",
+					Optional:    true,
+				},
+				"port": schema.Int64Attribute{
+					Description: "SSH port. Defaults to the port of host, then to 22",
+					Optional:    true,
+					Validators: []validator.Int64{
+						int64validator.Between(1, 65535),
+					},
+				},
+				"connect_timeout": schema.StringAttribute{
+					Description: "Timeout of the SSH connection and handshake, as a duration (e.g. 30s). Defaults to 30s",
+					Optional:    true,
+				},
+			},
+		},
 	},
 }
 
+// ManagerNodeSchema describes an optional connection to a manager of the swarm,
+// used by resources that act on a node through the swarm control plane.
+var ManagerNodeSchema = schema.SingleNestedAttribute{
+	Description: "Docker connection configuration for a manager node of the swarm.",
+	Optional:    true,
+	Attributes:  NodeSchema.Attributes,
+}
+
+// DataSourceNodeSchema is the data source counterpart of NodeSchema, built
+// from its attributes so that both stay in sync.
+var DataSourceNodeSchema = dsschema.SingleNestedAttribute{
+	Description: NodeSchema.Description,
+	Optional:    true,
+	Attributes:  dataSourceAttributes(NodeSchema.Attributes),
+}
+
+// dataSourceAttributes converts the resource attributes of a node connection
+// to data source attributes. Only the attribute types used by NodeSchema are
+// supported.
+func dataSourceAttributes(attributes map[string]schema.Attribute) map[string]dsschema.Attribute {
+	converted := make(map[string]dsschema.Attribute, len(attributes))
+	for name, attribute := range attributes {
+		switch a := attribute.(type) {
+		case schema.StringAttribute:
+			converted[name] = dsschema.StringAttribute{
+				Description: a.Description,
+				Optional:    a.Optional,
+				Sensitive:   a.Sensitive,
+				Validators:  a.Validators,
+			}
+		case schema.Int64Attribute:
+			converted[name] = dsschema.Int64Attribute{
+				Description: a.Description,
+				Optional:    a.Optional,
+				Sensitive:   a.Sensitive,
+				Validators:  a.Validators,
+			}
+		case schema.ListAttribute:
+			converted[name] = dsschema.ListAttribute{
+				Description: a.Description,
+				ElementType: a.ElementType,
+				Optional:    a.Optional,
+				Sensitive:   a.Sensitive,
+				Validators:  a.Validators,
+			}
+		case schema.SingleNestedAttribute:
+			converted[name] = dsschema.SingleNestedAttribute{
+				Description: a.Description,
+				Optional:    a.Optional,
+				Sensitive:   a.Sensitive,
+				Validators:  a.Validators,
+				Attributes:  dataSourceAttributes(a.Attributes),
+			}
+		default:
+			panic(fmt.Sprintf("unsupported node attribute %s of type %T", name, attribute))
+		}
+	}
+	return converted
+}
+
+// ID identifies the connection as "<host>[,<context>]", the form of the
+// import IDs. The host is empty when it is taken from the context.
+func (n TfNode) ID() string {
+	if n.Context.ValueString() == "" {
+		return n.Host.ValueString()
+	}
+	return n.Host.ValueString() + "," + n.Context.ValueString()
+}
+
 func ExtractConfig(node TfNode) Config {
 	sshOpts := []string{}
 	if !node.SSHOpts.IsNull() && !node.SSHOpts.IsUnknown() {
@@ -61,12 +192,26 @@ func ExtractConfig(node TfNode) Config {
 			}
 		}
 	}
-	return Config{
+	config := Config{
 		Host:     node.Host.ValueString(),
+		Context:  node.Context.ValueString(),
 		SSHOpts:  sshOpts,
 		Cert:     node.CertMaterial.ValueString(),
 		Key:      node.KeyMaterial.ValueString(),
 		Ca:       node.CaMaterial.ValueString(),
 		CertPath: node.CertPath.ValueString(),
 	}
+	if node.SSH != nil {
+		config.SSH = &SSHConfig{
+			PrivateKey:         node.SSH.PrivateKey.ValueString(),
+			Passphrase:         node.SSH.Passphrase.ValueString(),
+			AgentSocket:        node.SSH.AgentSocket.ValueString(),
+			KnownHosts:         node.SSH.KnownHosts.ValueString(),
+			HostKeyFingerprint: node.SSH.HostKeyFingerprint.ValueString(),
+			User:               node.SSH.User.ValueString(),
+			Port:               int(node.SSH.Port.ValueInt64()),
+			ConnectTimeout:     node.SSH.ConnectTimeout.ValueString(),
+		}
+	}
+	return config
 }
@@ -0,0 +1,22 @@
+package docker
+
+import (
+	"testing"
+
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataSourceNodeSchema(t *testing.T) {
+	assert.Equal(t, NodeSchema.Description, DataSourceNodeSchema.Description)
+	assert.Len(t, DataSourceNodeSchema.Attributes, len(NodeSchema.Attributes))
+	for name := range NodeSchema.Attributes {
+		assert.Contains(t, DataSourceNodeSchema.Attributes, name)
+	}
+
+	ssh, ok := DataSourceNodeSchema.Attributes["ssh"].(dsschema.SingleNestedAttribute)
+	assert.True(t, ok)
+	assert.True(t, ssh.Attributes["private_key"].IsSensitive())
+	assert.Len(t, ssh.Attributes["port"].(dsschema.Int64Attribute).Validators, 1)
+	assert.True(t, DataSourceNodeSchema.Attributes["host"].IsOptional())
+}
@@ -0,0 +1,136 @@
+package docker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/client"
+)
+
+const (
+	// poolHealthCheckInterval is the age after which a pooled client is
+	// pinged before being reused.
+	poolHealthCheckInterval = 30 * time.Second
+	// poolHealthCheckTimeout bounds the ping of a pooled client.
+	poolHealthCheckTimeout = 10 * time.Second
+)
+
+// Pool shares Docker clients between the resources of a provider, one per
+// connection. It is safe for concurrent use. The clients it returns belong
+// to the pool and must not be closed by the callers.
+type Pool struct {
+	mu      sync.Mutex
+	clients map[string]*pooledClient
+	closed  bool
+}
+
+// pooledClient is the client of a connection, created on first use.
+type pooledClient struct {
+	mu      sync.Mutex
+	client  *client.Client
+	checked time.Time
+	closed  bool
+}
+
+// NewPool returns an empty pool.
+func NewPool() *Pool {
+	return &Pool{clients: map[string]*pooledClient{}}
+}
+
+// Client returns the client of the connection, creating it on first use.
+// A client unused for a while is pinged first, and replaced when the daemon
+// cannot be reached through it anymore, e.g. after its SSH session died.
+func (p *Pool) Client(config Config) (*client.Client, error) {
+	resolved, err := config.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	key, err := resolved.poolKey()
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, errors.New("docker client pool is closed")
+	}
+	entry, ok := p.clients[key]
+	if !ok {
+		entry = &pooledClient{}
+		p.clients[key] = entry
+	}
+	p.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.closed {
+		return nil, errors.New("docker client pool is closed")
+	}
+
+	if entry.client != nil {
+		if time.Since(entry.checked) < poolHealthCheckInterval || entry.healthy() {
+			entry.checked = time.Now()
+			return entry.client, nil
+		}
+		entry.client.Close()
+		entry.client = nil
+	}
+
+	dockerClient, err := resolved.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	entry.client = dockerClient
+	entry.checked = time.Now()
+	return dockerClient, nil
+}
+
+// Close closes the clients of the pool, which cannot be used afterwards.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	for key, entry := range p.clients {
+		entry.mu.Lock()
+		if entry.client != nil {
+			errs = append(errs, entry.client.Close())
+			entry.client = nil
+		}
+		entry.closed = true
+		entry.mu.Unlock()
+		delete(p.clients, key)
+	}
+	p.closed = true
+	return errors.Join(errs...)
+}
+
+// healthy reports whether the daemon answers a ping through the client.
+func (e *pooledClient) healthy() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), poolHealthCheckTimeout)
+	defer cancel()
+
+	_, err := e.client.Ping(ctx)
+	return err == nil
+}
+
+// poolKey identifies the connection of a resolved config. The TLS material
+// is hashed with the rest of the settings rather than kept in the pool.
+func (c *Config) poolKey() (string, error) {
+	normalized := *c
+	if len(normalized.SSHOpts) == 0 {
+		normalized.SSHOpts = nil
+	}
+	content, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool_Client(t *testing.T) {
+	clearEnv(t)
+	pool := NewPool()
+	defer pool.Close()
+
+	first, err := pool.Client(Config{Host: "tcp://192.168.1.100:2376"})
+	assert.NoError(t, err)
+
+	// The same connection shares the client
+	again, err := pool.Client(Config{Host: "tcp://192.168.1.100:2376", SSHOpts: []string{}})
+	assert.NoError(t, err)
+	assert.Same(t, first, again)
+
+	// Another connection gets its own client
+	other, err := pool.Client(Config{Host: "tcp://192.168.1.100:2376", APIVersion: "1.41"})
+	assert.NoError(t, err)
+	assert.NotSame(t, first, other)
+
+	_, err = pool.Client(Config{Host: "tcp://192.168.1.100:2376", Cert: "cert-content"})
+	assert.Error(t, err)
+}
+
+func TestPool_ClientConcurrent(t *testing.T) {
+	clearEnv(t)
+	pool := NewPool()
+	defer pool.Close()
+
+	clients := make([]*client.Client, 10)
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clients[i], _ = pool.Client(Config{Host: "tcp://192.168.1.100:2376"})
+		}(i)
+	}
+	wg.Wait()
+
+	for _, dockerClient := range clients {
+		assert.NotNil(t, dockerClient)
+		assert.Same(t, clients[0], dockerClient)
+	}
+}
+
+func TestPool_ClientHealthCheck(t *testing.T) {
+	clearEnv(t)
+	pool := NewPool()
+	defer pool.Close()
+
+	// Nothing listens on the port, so the ping of a stale client fails
+	config := Config{Host: "tcp://127.0.0.1:1"}
+	first, err := pool.Client(config)
+	assert.NoError(t, err)
+
+	for _, entry := range pool.clients {
+		entry.checked = time.Now().Add(-2 * poolHealthCheckInterval)
+	}
+
+	replaced, err := pool.Client(config)
+	assert.NoError(t, err)
+	assert.NotSame(t, first, replaced)
+}
+
+func TestPool_Close(t *testing.T) {
+	clearEnv(t)
+	pool := NewPool()
+
+	_, err := pool.Client(Config{Host: "tcp://192.168.1.100:2376"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, pool.Close())
+	assert.Empty(t, pool.clients)
+
+	_, err = pool.Client(Config{Host: "tcp://192.168.1.100:2376"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package docker
+
+import (
+	"os"
+	"strings"
+
+	"github.com/docker/docker/client"
+)
+
+// Environment variables of the Docker CLI honoured by Resolve, in addition to
+// DOCKER_HOST, DOCKER_CERT_PATH and DOCKER_TLS_VERIFY.
+const (
+	// EnvOverrideContext selects the Docker CLI context.
+	EnvOverrideContext = "DOCKER_CONTEXT"
+	// EnvOverrideConfigDir is the configuration directory of the Docker CLI,
+	// holding config.json and the context store.
+	EnvOverrideConfigDir = "DOCKER_CONFIG"
+)
+
+// Resolve returns the connection settings completed from the environment and
+// the Docker CLI configuration, the way the Docker CLI does:
+//
+//  1. The attributes set explicitly. A context fills in the host and the TLS
+//     material that are not set.
+//  2. DOCKER_HOST, when neither a host nor a context is set.
+//  3. DOCKER_CONTEXT, or the current context of the Docker CLI.
+//  4. The local daemon at unix:///var/run/docker.sock.
+//
+// TLS material that is not set explicitly nor by a context is taken from
+// DOCKER_CERT_PATH and DOCKER_TLS_VERIFY for tcp:// hosts.
+func (c *Config) Resolve() (Config, error) {
+	resolved := *c
+	if resolved.resolved {
+		return resolved, nil
+	}
+
+	// Client material without CA certificate does not verify the daemon
+	if resolved.Ca == "" && (resolved.Cert != "" || resolved.Key != "") {
+		resolved.SkipTLSVerify = true
+	}
+
+	if resolved.Host == "" && resolved.Context == "" {
+		resolved.Host = os.Getenv(client.EnvOverrideHost)
+	}
+	if resolved.Host == "" && resolved.Context == "" {
+		name, err := currentContext()
+		if err != nil {
+			return Config{}, err
+		}
+		resolved.Context = name
+	}
+
+	if resolved.Context != "" {
+		endpoint, err := loadContext(resolved.Context)
+		if err != nil {
+			return Config{}, err
+		}
+		if resolved.Host == "" {
+			resolved.Host = endpoint.Host
+		}
+		if !resolved.hasTLS() && !isSSHHost(resolved.Host) {
+			resolved.SkipTLSVerify = endpoint.SkipTLSVerify
+			if endpoint.TLSData != nil {
+				resolved.Ca = string(endpoint.TLSData.CA)
+				resolved.Cert = string(endpoint.TLSData.Cert)
+				resolved.Key = string(endpoint.TLSData.Key)
+			}
+		}
+		resolved.Context = ""
+	} else if !resolved.hasTLS() && isTCPHost(resolved.Host) {
+		resolved.tlsFromEnv()
+	}
+
+	if resolved.Host == "" {
+		resolved.Host = client.DefaultDockerHost
+	}
+	resolved.resolved = true
+	return resolved, nil
+}
+
+// tlsFromEnv sets the TLS material from DOCKER_CERT_PATH, verifying the
+// daemon when DOCKER_TLS_VERIFY is set. As with the Docker CLI,
+// DOCKER_TLS_VERIFY alone uses the certificates of ~/.docker.
+func (c *Config) tlsFromEnv() {
+	certPath := os.Getenv(client.EnvOverrideCertPath)
+	verify := os.Getenv(client.EnvTLSVerify) != ""
+	if certPath == "" && verify {
+		certPath = configDir()
+	}
+	if certPath == "" {
+		return
+	}
+	c.CertPath = certPath
+	c.SkipTLSVerify = !verify
+}
+
+// hasTLS reports whether TLS material is configured explicitly.
+func (c *Config) hasTLS() bool {
+	return c.Ca != "" || c.Cert != "" || c.Key != "" || c.CertPath != "" || c.KeyPath != "" || c.CaPath != ""
+}
+
+func isSSHHost(host string) bool {
+	return strings.HasPrefix(host, "ssh://")
+}
+
+func isTCPHost(host string) bool {
+	return strings.HasPrefix(host, "tcp://") || strings.HasPrefix(host, "https://")
+}
@@ -0,0 +1,153 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/cli/cli/context/docker"
+	"github.com/docker/docker/client"
+	"github.com/stretchr/testify/assert"
+)
+
+// clearEnv isolates the test from the Docker environment of the host.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	for _, name := range []string{"DOCKER_HOST", "DOCKER_CERT_PATH", "DOCKER_TLS_VERIFY", "DOCKER_CONTEXT"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestConfig_Resolve(t *testing.T) {
+	tests := []struct {
+		name         string
+		env          map[string]string
+		config       Config
+		wantHost     string
+		wantCertPath string
+		wantSkipTLS  bool
+		wantCa       string
+		wantErr      bool
+	}{
+		{
+			name:     "default host",
+			wantHost: client.DefaultDockerHost,
+		},
+		{
+			name:     "DOCKER_HOST",
+			env:      map[string]string{"DOCKER_HOST": "tcp://192.168.1.100:2375"},
+			wantHost: "tcp://192.168.1.100:2375",
+		},
+		{
+			name:     "explicit host overrides DOCKER_HOST",
+			env:      map[string]string{"DOCKER_HOST": "tcp://192.168.1.100:2375"},
+			config:   Config{Host: "ssh://root@192.168.1.101"},
+			wantHost: "ssh://root@192.168.1.101",
+		},
+		{
+			name:         "DOCKER_CERT_PATH without verification",
+			env:          map[string]string{"DOCKER_HOST": "tcp://192.168.1.100:2376", "DOCKER_CERT_PATH": "/certs"},
+			wantHost:     "tcp://192.168.1.100:2376",
+			wantCertPath: "/certs",
+			wantSkipTLS:  true,
+		},
+		{
+			name:         "DOCKER_CERT_PATH with DOCKER_TLS_VERIFY",
+			env:          map[string]string{"DOCKER_CERT_PATH": "/certs", "DOCKER_TLS_VERIFY": "1"},
+			config:       Config{Host: "tcp://192.168.1.100:2376"},
+			wantHost:     "tcp://192.168.1.100:2376",
+			wantCertPath: "/certs",
+		},
+		{
+			name:         "explicit cert_path overrides DOCKER_CERT_PATH",
+			env:          map[string]string{"DOCKER_CERT_PATH": "/certs"},
+			config:       Config{Host: "tcp://192.168.1.100:2376", CertPath: "/node-certs"},
+			wantHost:     "tcp://192.168.1.100:2376",
+			wantCertPath: "/node-certs",
+		},
+		{
+			name:     "DOCKER_CERT_PATH ignored for ssh hosts",
+			env:      map[string]string{"DOCKER_CERT_PATH": "/certs", "DOCKER_TLS_VERIFY": "1"},
+			config:   Config{Host: "ssh://root@192.168.1.101"},
+			wantHost: "ssh://root@192.168.1.101",
+		},
+		{
+			name:        "client material without CA",
+			config:      Config{Host: "tcp://192.168.1.100:2376", Cert: "cert-content", Key: "key-content"},
+			wantHost:    "tcp://192.168.1.100:2376",
+			wantSkipTLS: true,
+		},
+		{
+			name:     "DOCKER_CONTEXT",
+			env:      map[string]string{"DOCKER_CONTEXT": "prod"},
+			wantHost: "tcp://192.168.1.200:2376",
+			wantCa:   "ca-content",
+		},
+		{
+			name:     "DOCKER_HOST overrides DOCKER_CONTEXT",
+			env:      map[string]string{"DOCKER_CONTEXT": "prod", "DOCKER_HOST": "tcp://192.168.1.100:2375"},
+			wantHost: "tcp://192.168.1.100:2375",
+		},
+		{
+			name:     "context attribute overrides DOCKER_HOST",
+			env:      map[string]string{"DOCKER_HOST": "tcp://192.168.1.100:2375"},
+			config:   Config{Context: "prod"},
+			wantHost: "tcp://192.168.1.200:2376",
+			wantCa:   "ca-content",
+		},
+		{
+			name:     "explicit host overrides the context",
+			config:   Config{Host: "tcp://192.168.1.201:2376", Context: "prod"},
+			wantHost: "tcp://192.168.1.201:2376",
+			wantCa:   "ca-content",
+		},
+		{
+			name:    "unknown DOCKER_CONTEXT",
+			env:     map[string]string{"DOCKER_CONTEXT": "staging"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			writeContext(t, "prod", docker.EndpointMeta{Host: "tcp://192.168.1.200:2376"}, map[string][]byte{
+				"ca.pem": []byte("ca-content"),
+			})
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			resolved, err := tt.config.Resolve()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHost, resolved.Host)
+			assert.Equal(t, tt.wantCertPath, resolved.CertPath)
+			assert.Equal(t, tt.wantSkipTLS, resolved.SkipTLSVerify)
+			assert.Equal(t, tt.wantCa, resolved.Ca)
+			assert.Empty(t, resolved.Context)
+		})
+	}
+}
+
+func TestConfig_ResolveCurrentContext(t *testing.T) {
+	clearEnv(t)
+	writeContext(t, "prod", docker.EndpointMeta{Host: "tcp://192.168.1.200:2376"}, nil)
+	err := os.WriteFile(filepath.Join(os.Getenv("DOCKER_CONFIG"), "config.json"), []byte(`{"currentContext": "prod"}`), 0o600)
+	assert.NoError(t, err)
+
+	config := Config{}
+	resolved, err := config.Resolve()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://192.168.1.200:2376", resolved.Host)
+
+	// Resolving again keeps the settings
+	again, err := resolved.Resolve()
+	assert.NoError(t, err)
+	assert.Equal(t, resolved, again)
+}
@@ -0,0 +1,367 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	// defaultSSHPort is the port of ssh:// hosts without port.
+	defaultSSHPort = 22
+	// defaultSSHConnectTimeout bounds the connection and handshake with
+	// ssh:// hosts when no connect timeout is configured.
+	defaultSSHConnectTimeout = 30 * time.Second
+	// defaultSSHDockerSocket is the socket dialed on ssh:// hosts without
+	// path.
+	defaultSSHDockerSocket = "/var/run/docker.sock"
+)
+
+// SSHConfig configures the in-process SSH transport of ssh:// hosts, used
+// instead of the ssh binary.
+type SSHConfig struct {
+	// PrivateKey is the PEM-encoded private key, protected by Passphrase
+	PrivateKey string
+	Passphrase string
+	// AgentSocket is the socket of the SSH agent, SSH_AUTH_SOCK by default
+	// when no private key is set
+	AgentSocket string
+	// KnownHosts is the content of a known_hosts file
+	KnownHosts string
+	// HostKeyFingerprint pins the SHA256 fingerprint of the host key, as
+	// printed by ssh-keygen -l
+	HostKeyFingerprint string
+	// User and Port override the ones of the host URL
+	User string
+	Port int
+	// ConnectTimeout is a Go duration (e.g. "30s")
+	ConnectTimeout string
+}
+
+// sshDialer dials the Docker socket of a host over SSH. The SSH connection
+// is opened on the first dial and shared by the connections of the Docker
+// client, then closed with the last of them.
+type sshDialer struct {
+	addr        string
+	user        string
+	socket      string
+	timeout     time.Duration
+	agentSocket string
+	config      SSHConfig
+	hostKey     ssh.HostKeyCallback
+	signer      ssh.Signer
+
+	mu      sync.Mutex
+	session *sshSession
+}
+
+// sshSession is an SSH connection and the number of Docker connections
+// open through it.
+type sshSession struct {
+	client *ssh.Client
+	conns  int
+}
+
+// newSSHDialer returns the dialer of an ssh:// host. The user, port and
+// socket path are taken from the host URL unless configured.
+func newSSHDialer(host string, config SSHConfig) (*sshDialer, error) {
+	hostURL, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ssh host '%s': %w", host, err)
+	}
+	if hostURL.Scheme != "ssh" || hostURL.Hostname() == "" {
+		return nil, fmt.Errorf("invalid ssh host '%s': expected ssh://[user@]host[:port][/socket]", host)
+	}
+	if config.KnownHosts == "" && config.HostKeyFingerprint == "" {
+		return nil, errors.New("ssh requires known_hosts or host_key_fingerprint to verify the host key")
+	}
+
+	dialer := &sshDialer{
+		user:    config.User,
+		socket:  hostURL.Path,
+		timeout: defaultSSHConnectTimeout,
+		config:  config,
+	}
+	if dialer.user == "" {
+		dialer.user = hostURL.User.Username()
+	}
+	if dialer.user == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("ssh user is not set and the current user is unknown: %w", err)
+		}
+		dialer.user = current.Username
+	}
+
+	port := config.Port
+	if port == 0 && hostURL.Port() != "" {
+		port, err = strconv.Atoi(hostURL.Port())
+		if err != nil {
+			return nil, fmt.Errorf("invalid ssh port '%s': %w", hostURL.Port(), err)
+		}
+	}
+	if port == 0 {
+		port = defaultSSHPort
+	}
+	dialer.addr = net.JoinHostPort(hostURL.Hostname(), strconv.Itoa(port))
+
+	if dialer.socket == "" || dialer.socket == "/" {
+		dialer.socket = defaultSSHDockerSocket
+	}
+	if config.ConnectTimeout != "" {
+		dialer.timeout, err = time.ParseDuration(config.ConnectTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ssh connect_timeout '%s': %w", config.ConnectTimeout, err)
+		}
+	}
+
+	dialer.hostKey, err = hostKeyCallback(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.PrivateKey != "" {
+		dialer.signer, err = parsePrivateKey(config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	dialer.agentSocket = config.AgentSocket
+	if dialer.agentSocket == "" && config.PrivateKey == "" {
+		dialer.agentSocket = os.Getenv("SSH_AUTH_SOCK")
+	}
+	if dialer.signer == nil && dialer.agentSocket == "" {
+		return nil, errors.New("ssh requires private_key or an ssh agent to authenticate")
+	}
+	return dialer, nil
+}
+
+// DialContext opens a connection to the Docker socket of the host. The
+// network and address asked by the Docker client are ignored.
+func (d *sshDialer) DialContext(ctx context.Context, _, _ string) (net.Conn, error) {
+	session, err := d.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := session.client.Dial("unix", d.socket)
+	if err != nil {
+		d.release(session)
+		return nil, fmt.Errorf("could not dial %s on %s over ssh: %w", d.socket, d.addr, err)
+	}
+	return &sshConn{Conn: conn, release: func() { d.release(session) }}, nil
+}
+
+// acquire returns the SSH connection, opening it when needed, and counts a
+// Docker connection on it.
+func (d *sshDialer) acquire(ctx context.Context) (*sshSession, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.session == nil {
+		sshClient, err := d.connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		session := &sshSession{client: sshClient}
+		d.session = session
+
+		// Forget the connection when it breaks, the next dial reconnects
+		go func() {
+			_ = sshClient.Wait()
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			if d.session == session {
+				d.session = nil
+			}
+		}()
+	}
+	d.session.conns++
+	return d.session, nil
+}
+
+// release uncounts a Docker connection, closing the SSH connection with the
+// last one.
+func (d *sshDialer) release(session *sshSession) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	session.conns--
+	if session.conns > 0 {
+		return
+	}
+	_ = session.client.Close()
+	if d.session == session {
+		d.session = nil
+	}
+}
+
+// connect opens and authenticates the SSH connection.
+func (d *sshDialer) connect(ctx context.Context) (*ssh.Client, error) {
+	clientConfig := &ssh.ClientConfig{
+		User:            d.user,
+		HostKeyCallback: d.hostKey,
+	}
+	if d.config.KnownHosts != "" {
+		clientConfig.HostKeyAlgorithms = knownHostKeyAlgorithms(d.config.KnownHosts)
+	}
+	if d.signer != nil {
+		clientConfig.Auth = append(clientConfig.Auth, ssh.PublicKeys(d.signer))
+	}
+	if d.agentSocket != "" {
+		agentConn, err := net.Dial("unix", d.agentSocket)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to ssh agent %s: %w", d.agentSocket, err)
+		}
+		// The agent signs during the handshake only
+		defer agentConn.Close()
+		clientConfig.Auth = append(clientConfig.Auth, ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+	}
+
+	netDialer := net.Dialer{Timeout: d.timeout}
+	conn, err := netDialer.DialContext(ctx, "tcp", d.addr)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to %s: %w", d.addr, err)
+	}
+	if err := conn.SetDeadline(time.Now().Add(d.timeout)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, d.addr, clientConfig)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("could not open ssh connection to %s: %w", d.addr, err)
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		sshConn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+// parsePrivateKey parses the private key, with its passphrase when set.
+func parsePrivateKey(config SSHConfig) (ssh.Signer, error) {
+	var signer ssh.Signer
+	var err error
+	if config.Passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(config.PrivateKey), []byte(config.Passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey([]byte(config.PrivateKey))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse ssh private_key: %w", err)
+	}
+	return signer, nil
+}
+
+// hostKeyCallback verifies the host key against the known_hosts content
+// and the pinned fingerprint, both when both are set.
+func hostKeyCallback(config SSHConfig) (ssh.HostKeyCallback, error) {
+	var callbacks []ssh.HostKeyCallback
+
+	if config.KnownHosts != "" {
+		callback, err := knownHostsCallback(config.KnownHosts)
+		if err != nil {
+			return nil, err
+		}
+		callbacks = append(callbacks, callback)
+	}
+	if config.HostKeyFingerprint != "" {
+		fingerprint := config.HostKeyFingerprint
+		if !strings.HasPrefix(fingerprint, "SHA256:") {
+			fingerprint = "SHA256:" + fingerprint
+		}
+		callbacks = append(callbacks, func(hostname string, _ net.Addr, key ssh.PublicKey) error {
+			if actual := ssh.FingerprintSHA256(key); actual != fingerprint {
+				return fmt.Errorf("ssh host key of %s has fingerprint %s, expected %s", hostname, actual, fingerprint)
+			}
+			return nil
+		})
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		for _, callback := range callbacks {
+			if err := callback(hostname, remote, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+// knownHostsCallback parses the content of a known_hosts file. The
+// knownhosts package reads files only, so the content goes through a
+// temporary file.
+func knownHostsCallback(content string) (ssh.HostKeyCallback, error) {
+	file, err := os.CreateTemp("", "known_hosts")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	callback, err := knownhosts.New(file.Name())
+	if err != nil {
+		return nil, fmt.Errorf("could not parse ssh known_hosts: %w", err)
+	}
+	return callback, nil
+}
+
+// knownHostKeyAlgorithms returns the algorithms of the keys listed in the
+// known_hosts content, so that the host presents one of them.
+func knownHostKeyAlgorithms(content string) []string {
+	var algorithms []string
+	seen := map[string]bool{}
+	add := func(algorithm string) {
+		if !seen[algorithm] {
+			seen[algorithm] = true
+			algorithms = append(algorithms, algorithm)
+		}
+	}
+
+	rest := []byte(content)
+	for len(rest) > 0 {
+		_, _, key, _, next, err := ssh.ParseKnownHosts(rest)
+		if err != nil {
+			break
+		}
+		rest = next
+		if key.Type() == ssh.KeyAlgoRSA {
+			add(ssh.KeyAlgoRSASHA512)
+			add(ssh.KeyAlgoRSASHA256)
+		}
+		add(key.Type())
+	}
+	return algorithms
+}
+
+// sshConn is a Docker connection over SSH, uncounted from its SSH
+// connection once closed.
+type sshConn struct {
+	net.Conn
+	release func()
+	once    sync.Once
+}
+
+func (c *sshConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.release)
+	return err
+}
@@ -0,0 +1,233 @@
+package docker
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sshTestServer is an SSH server forwarding direct-streamlocal channels to a
+// fake Docker daemon answering pings.
+type sshTestServer struct {
+	addr       string
+	hostKey    ssh.PublicKey
+	privateKey string
+	socket     string
+}
+
+func newSSHTestServer(t *testing.T) *sshTestServer {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	daemon, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	go func() {
+		_ = http.Serve(daemon, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Api-Version", "1.43")
+			_, _ = w.Write([]byte("OK"))
+		}))
+	}()
+	t.Cleanup(func() { daemon.Close() })
+
+	_, hostPrivate, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostPrivate)
+	assert.NoError(t, err)
+
+	userPublic, userPrivate, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	userKey, err := ssh.NewPublicKey(userPublic)
+	assert.NoError(t, err)
+	userPEM, err := ssh.MarshalPrivateKey(userPrivate, "")
+	assert.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if meta.User() == "docker" && string(key.Marshal()) == string(userKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown key for %s", meta.User())
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, config)
+		}
+	}()
+
+	return &sshTestServer{
+		addr:       listener.Addr().String(),
+		hostKey:    hostSigner.PublicKey(),
+		privateKey: string(pem.EncodeToMemory(userPEM)),
+		socket:     socket,
+	}
+}
+
+func serveSSH(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "direct-streamlocal@openssh.com" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+		var target struct {
+			SocketPath string
+			Reserved0  string
+			Reserved1  uint32
+		}
+		if err := ssh.Unmarshal(newChannel.ExtraData(), &target); err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		socketConn, err := net.Dial("unix", target.SocketPath)
+		if err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		channel, channelReqs, err := newChannel.Accept()
+		if err != nil {
+			socketConn.Close()
+			continue
+		}
+		go ssh.DiscardRequests(channelReqs)
+		go func() {
+			_, _ = io.Copy(channel, socketConn)
+			channel.Close()
+		}()
+		go func() {
+			_, _ = io.Copy(socketConn, channel)
+			socketConn.Close()
+		}()
+	}
+}
+
+func (s *sshTestServer) host() string {
+	return "ssh://docker@" + s.addr + s.socket
+}
+
+func TestConfig_NewClientSSH(t *testing.T) {
+	server := newSSHTestServer(t)
+
+	config := Config{
+		Host:       server.host(),
+		APIVersion: "1.43",
+		SSH: &SSHConfig{
+			PrivateKey:         server.privateKey,
+			HostKeyFingerprint: ssh.FingerprintSHA256(server.hostKey),
+		},
+	}
+	dockerClient, err := config.NewClient()
+	assert.NoError(t, err)
+	defer dockerClient.Close()
+
+	ping, err := dockerClient.Ping(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "1.43", ping.APIVersion)
+}
+
+func TestConfig_NewClientSSHKnownHosts(t *testing.T) {
+	server := newSSHTestServer(t)
+
+	config := Config{
+		Host:       server.host(),
+		APIVersion: "1.43",
+		SSH: &SSHConfig{
+			PrivateKey: server.privateKey,
+			KnownHosts: knownhosts.Line([]string{knownhosts.Normalize(server.addr)}, server.hostKey),
+		},
+	}
+	dockerClient, err := config.NewClient()
+	assert.NoError(t, err)
+	defer dockerClient.Close()
+
+	_, err = dockerClient.Ping(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestConfig_NewClientSSHHostKeyMismatch(t *testing.T) {
+	server := newSSHTestServer(t)
+	other := newSSHTestServer(t)
+
+	config := Config{
+		Host:       server.host(),
+		APIVersion: "1.43",
+		SSH: &SSHConfig{
+			PrivateKey:         server.privateKey,
+			HostKeyFingerprint: ssh.FingerprintSHA256(other.hostKey),
+		},
+	}
+	dockerClient, err := config.NewClient()
+	assert.NoError(t, err)
+	defer dockerClient.Close()
+
+	_, err = dockerClient.Ping(context.Background())
+	assert.ErrorContains(t, err, "fingerprint")
+}
+
+func TestNewSSHDialer(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	agent := SSHConfig{AgentSocket: "/run/ssh-agent.sock", HostKeyFingerprint: "SHA256:abc"}
+
+	dialer, err := newSSHDialer("ssh://root@192.168.1.100", agent)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.100:22", dialer.addr)
+	assert.Equal(t, "root", dialer.user)
+	assert.Equal(t, defaultSSHDockerSocket, dialer.socket)
+
+	// The attributes override the URL
+	agent.User = "docker"
+	agent.Port = 2222
+	dialer, err = newSSHDialer("ssh://root@192.168.1.100:22/run/docker.sock", agent)
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.100:2222", dialer.addr)
+	assert.Equal(t, "docker", dialer.user)
+	assert.Equal(t, "/run/docker.sock", dialer.socket)
+
+	invalid := []SSHConfig{
+		// The host key is not verified
+		{AgentSocket: "/run/ssh-agent.sock"},
+		// Nothing authenticates the user
+		{HostKeyFingerprint: "SHA256:abc"},
+		{PrivateKey: "not a key", HostKeyFingerprint: "SHA256:abc"},
+		{AgentSocket: "/run/ssh-agent.sock", HostKeyFingerprint: "SHA256:abc", ConnectTimeout: "soon"},
+	}
+	for _, config := range invalid {
+		_, err := newSSHDialer("ssh://root@192.168.1.100", config)
+		assert.Error(t, err)
+	}
+
+	_, err = newSSHDialer("tcp://192.168.1.100:2376", agent)
+	assert.Error(t, err)
+}
+
+func TestKnownHostKeyAlgorithms(t *testing.T) {
+	server := newSSHTestServer(t)
+
+	algorithms := knownHostKeyAlgorithms("# comment\n" + knownhosts.Line([]string{knownhosts.Normalize(server.addr)}, server.hostKey))
+
+	assert.Equal(t, []string{ssh.KeyAlgoED25519}, algorithms)
+}
@@ -2,9 +2,9 @@ package provider
 
 import (
 	"context"
-	"os"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -18,13 +18,24 @@ var (
 	_ provider.Provider = &swarmProvider{}
 )
 
-// New returns a new provider.
+// New returns a new provider, with its own pool of Docker clients.
 func New() provider.Provider {
-	return &swarmProvider{}
+	return &swarmProvider{clients: docker.NewPool()}
+}
+
+// NewWithClients returns a factory of providers sharing the pool of Docker
+// clients, which the caller closes on shutdown.
+func NewWithClients(clients *docker.Pool) func() provider.Provider {
+	return func() provider.Provider {
+		return &swarmProvider{clients: clients}
+	}
 }
 
 // swarmProvider is the provider implementation.
-type swarmProvider struct{}
+type swarmProvider struct {
+	// clients is shared by the resources and data sources of the provider
+	clients *docker.Pool
+}
 
 // swarmProviderModel maps provider schema data to a Go type.
 
@@ -39,7 +50,11 @@ func (p *swarmProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 		Description: "The Swarm provider allows you to manage Docker Swarm clusters.",
 		Attributes: map[string]schema.Attribute{
 			"host": schema.StringAttribute{
-				Description: "Docker daemon host. Defaults to unix:///var/run/docker.sock",
+				Description: "Docker daemon host. Defaults to DOCKER_HOST, then to the Docker CLI context, then to unix:///var/run/docker.sock",
+				Optional:    true,
+			},
+			"context": schema.StringAttribute{
+				Description: "Name of a Docker CLI context providing the host and TLS material. Defaults to DOCKER_CONTEXT, then to the current context of the Docker CLI",
 				Optional:    true,
 			},
 			"cert_path": schema.StringAttribute{
@@ -47,19 +62,19 @@ func (p *swarmProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp
 				Optional:    true,
 			},
 			"key_path": schema.StringAttribute{
-				Description: "Path to Docker client private key",
+				Description: "Path to Docker client private key, overriding key.pem of cert_path",
 				Optional:    true,
 			},
 			"ca_path": schema.StringAttribute{
-				Description: "Path to Docker CA certificate",
+				Description: "Path to Docker CA certificate, overriding ca.pem of cert_path",
 				Optional:    true,
 			},
 			"api_version": schema.StringAttribute{
-				Description: "Docker API version to use",
+				Description: "Docker API version to use instead of negotiating it with the daemon",
 				Optional:    true,
 			},
 			"registry_auth": schema.MapAttribute{
-				Description: "Registry authentication configuration",
+				Description: "Registry credentials sent when creating or updating services: address, username, password or identity_token",
 				ElementType: types.StringType,
 				Optional:    true,
 				Sensitive:   true,
@@ -79,27 +94,43 @@ func (p *swarmProvider) Configure(ctx context.Context, req provider.ConfigureReq
 		return
 	}
 
-	// Set defaults if not specified
-	host := config.Host.ValueString()
-	if host == "" {
-		if dockerHost := os.Getenv("DOCKER_HOST"); dockerHost != "" {
-			host = dockerHost
-		} else {
-			host = "unix:///var/run/docker.sock"
-		}
+	// Create Docker client configuration, completed from the environment
+	// and the Docker CLI configuration by docker.Config.Resolve
+	defaultConfig := &resources.DockerClientConfig{
+		Host:       config.Host.ValueString(),
+		Context:    config.Context.ValueString(),
+		CertPath:   config.CertPath.ValueString(),
+		KeyPath:    config.KeyPath.ValueString(),
+		CaPath:     config.CaPath.ValueString(),
+		APIVersion: config.APIVersion.ValueString(),
 	}
-
-	// Create Docker client configuration
-	dockerConfig := &docker.Config{
-		Host: host,
+	providerData := &resources.SwarmProviderData{
+		NodeConfigs: map[string]*resources.DockerClientConfig{
+			"default": defaultConfig,
+		},
+		Clients: p.clients,
 	}
 
-	if !config.CertPath.IsNull() {
-		dockerConfig.CertPath = config.CertPath.ValueString()
+	if !config.RegistryAuth.IsNull() && !config.RegistryAuth.IsUnknown() {
+		registryAuth := map[string]string{}
+		resp.Diagnostics.Append(config.RegistryAuth.ElementsAs(ctx, &registryAuth, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		encoded, err := docker.EncodeRegistryAuth(registryAuth)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("registry_auth"),
+				"Invalid Registry Authentication",
+				"Could not encode registry_auth: "+err.Error(),
+			)
+			return
+		}
+		providerData.RegistryAuth = encoded
 	}
 
 	// Create the Docker client
-	dockerClient, err := dockerConfig.NewClient()
+	dockerClient, err := providerData.NewClient(nil)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Docker Client",
@@ -121,27 +152,19 @@ func (p *swarmProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	}
 
 	// Store configuration for use in resources
-	providerData := &resources.SwarmProviderData{
-		NodeConfigs: map[string]*resources.DockerClientConfig{
-			"default": {
-				Host:       host,
-				CertPath:   config.CertPath.ValueString(),
-				APIVersion: config.APIVersion.ValueString(),
-			},
-		},
-	}
-
 	resp.DataSourceData = providerData
 	resp.ResourceData = providerData
 
 	tflog.Info(ctx, "Configured Swarm provider", map[string]any{
-		"host": host,
+		"host": dockerClient.DaemonHost(),
 	})
 }
 
 // DataSources defines the data sources implemented in the provider.
 func (p *swarmProvider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewServiceDataSource,
+	}
 }
 
 // Resources defines the resources implemented in the provider.
@@ -149,6 +172,10 @@ func (p *swarmProvider) Resources(_ context.Context) []func() resource.Resource
 	return []func() resource.Resource{
 		resources.NewSwarmInitResource,
 		resources.NewSwarmJoinResource,
+		resources.NewSwarmUnlockResource,
+		resources.NewSwarmNodeResource,
+		resources.NewSwarmClusterResource,
+		NewServiceResource,
 	}
 }
 
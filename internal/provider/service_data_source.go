@@ -3,15 +3,28 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sntns/terraform-provider-swarm/internal/docker"
+	"github.com/sntns/terraform-provider-swarm/internal/resources"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ datasource.DataSource = &ServiceDataSource{}
+var _ datasource.DataSourceWithConfigValidators = &ServiceDataSource{}
 
 func NewServiceDataSource() datasource.DataSource {
 	return &ServiceDataSource{}
@@ -19,16 +32,30 @@ func NewServiceDataSource() datasource.DataSource {
 
 // ServiceDataSource defines the data source implementation.
 type ServiceDataSource struct {
-	client *SwarmClient
+	providerData *resources.SwarmProviderData
 }
 
 // ServiceDataSourceModel describes the data source data model.
 type ServiceDataSourceModel struct {
-	Id                    types.String `tfsdk:"id"`
-	Name                  types.String `tfsdk:"name"`
-	Image                 types.String `tfsdk:"image"`
-	Replicas              types.Int64  `tfsdk:"replicas"`
-	ConfigurableAttribute types.String `tfsdk:"configurable_attribute"`
+	Id           types.String                  `tfsdk:"id"`
+	Node         *docker.TfNode                `tfsdk:"node"`
+	Name         types.String                  `tfsdk:"name"`
+	LabelFilter  types.Map                     `tfsdk:"label_filter"`
+	Image        types.String                  `tfsdk:"image"`
+	Mode         types.String                  `tfsdk:"mode"`
+	Replicas     types.Int64                   `tfsdk:"replicas"`
+	Ports        []ServicePortModel            `tfsdk:"ports"`
+	Networks     types.List                    `tfsdk:"networks"`
+	Labels       types.Map                     `tfsdk:"labels"`
+	UpdateStatus *ServiceUpdateStatusDataModel `tfsdk:"update_status"`
+}
+
+// ServiceUpdateStatusDataModel describes the status of the latest service update.
+type ServiceUpdateStatusDataModel struct {
+	State       types.String `tfsdk:"state"`
+	Message     types.String `tfsdk:"message"`
+	StartedAt   types.String `tfsdk:"started_at"`
+	CompletedAt types.String `tfsdk:"completed_at"`
 }
 
 func (d *ServiceDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -41,78 +68,285 @@ func (d *ServiceDataSource) Schema(ctx context.Context, req datasource.SchemaReq
 		MarkdownDescription: "Swarm service data source",
 
 		Attributes: map[string]schema.Attribute{
-			"configurable_attribute": schema.StringAttribute{
-				MarkdownDescription: "Service configurable attribute",
-				Computed:            true,
-			},
+			"node": docker.DataSourceNodeSchema,
 			"id": schema.StringAttribute{
 				MarkdownDescription: "Service identifier",
-				Required:            true,
+				Optional:            true,
+				Computed:            true,
 			},
 			"name": schema.StringAttribute{
 				MarkdownDescription: "Service name",
+				Optional:            true,
 				Computed:            true,
 			},
+			"label_filter": schema.MapAttribute{
+				MarkdownDescription: "Labels the service must carry. An empty value only requires the label key to be present",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
 			"image": schema.StringAttribute{
 				MarkdownDescription: "Container image for the service",
 				Computed:            true,
 			},
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "Scheduling mode of the service",
+				Computed:            true,
+			},
 			"replicas": schema.Int64Attribute{
-				MarkdownDescription: "Number of service replicas",
+				MarkdownDescription: "Number of service replicas (0 in `global` mode)",
+				Computed:            true,
+			},
+			"ports": schema.ListNestedAttribute{
+				MarkdownDescription: "Ports published by the service",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"target_port": schema.Int64Attribute{
+							MarkdownDescription: "Port inside the container",
+							Computed:            true,
+						},
+						"published_port": schema.Int64Attribute{
+							MarkdownDescription: "Port published on the swarm hosts",
+							Computed:            true,
+						},
+						"protocol": schema.StringAttribute{
+							MarkdownDescription: "Port protocol",
+							Computed:            true,
+						},
+						"publish_mode": schema.StringAttribute{
+							MarkdownDescription: "Publish mode",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"networks": schema.ListAttribute{
+				MarkdownDescription: "Names of the networks the service is attached to",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Labels attached to the service",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"update_status": schema.SingleNestedAttribute{
+				MarkdownDescription: "Status of the latest service update, if any",
 				Computed:            true,
+				Attributes: map[string]schema.Attribute{
+					"state": schema.StringAttribute{
+						MarkdownDescription: "Update state (e.g. `updating`, `completed`, `rollback_completed`)",
+						Computed:            true,
+					},
+					"message": schema.StringAttribute{
+						MarkdownDescription: "Message reported by the orchestrator",
+						Computed:            true,
+					},
+					"started_at": schema.StringAttribute{
+						MarkdownDescription: "RFC3339 timestamp of the update start",
+						Computed:            true,
+					},
+					"completed_at": schema.StringAttribute{
+						MarkdownDescription: "RFC3339 timestamp of the update completion",
+						Computed:            true,
+					},
+				},
 			},
 		},
 	}
 }
 
+func (d *ServiceDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(
+			path.MatchRoot("id"),
+			path.MatchRoot("name"),
+			path.MatchRoot("label_filter"),
+		),
+	}
+}
+
+// The data source uses the connection described by its node block, or the
+// default connection of the provider.
 func (d *ServiceDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
-	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
+	providerData, diags := resources.ConfiguredProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	d.providerData = providerData
+}
 
-	client, ok := req.ProviderData.(*SwarmClient)
+func (d *ServiceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ServiceDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	if !ok {
+	dockerClient, err := d.providerData.NewClient(data.Node)
+	if err != nil {
 		resp.Diagnostics.AddError(
-			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *SwarmClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			"Unable to Create Docker Client",
+			"An unexpected error occurred when creating the Docker client. \n\nDocker Client Error: "+err.Error(),
 		)
+		return
+	}
 
+	service, lookupDiags := lookupService(ctx, dockerClient, &data)
+	resp.Diagnostics.Append(lookupDiags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	d.client = client
-}
+	data.Id = types.StringValue(service.ID)
+	data.Name = types.StringValue(service.Spec.Name)
 
-func (d *ServiceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	var data ServiceDataSourceModel
+	if service.Spec.TaskTemplate.ContainerSpec != nil {
+		data.Image = types.StringValue(service.Spec.TaskTemplate.ContainerSpec.Image)
+	} else {
+		data.Image = types.StringValue("")
+	}
 
-	// Read Terraform configuration data into the model
-	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	data.Mode = types.StringValue(serviceModeReplicated)
+	data.Replicas = types.Int64Value(0)
+	switch {
+	case service.Spec.Mode.Global != nil:
+		data.Mode = types.StringValue(serviceModeGlobal)
+	case service.Spec.Mode.Replicated != nil && service.Spec.Mode.Replicated.Replicas != nil:
+		data.Replicas = types.Int64Value(int64(*service.Spec.Mode.Replicated.Replicas))
+	}
 
-	if resp.Diagnostics.HasError() {
-		return
+	// Endpoint ports include the ports assigned by the daemon
+	data.Ports = []ServicePortModel{}
+	for _, port := range service.Endpoint.Ports {
+		data.Ports = append(data.Ports, ServicePortModel{
+			TargetPort:    types.Int64Value(int64(port.TargetPort)),
+			PublishedPort: types.Int64Value(int64(port.PublishedPort)),
+			Protocol:      types.StringValue(string(port.Protocol)),
+			PublishMode:   types.StringValue(string(port.PublishMode)),
+		})
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := d.client.ReadService(...)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read service, got error: %s", err))
-	//     return
-	// }
+	networks := []string{}
+	for _, attachment := range service.Spec.TaskTemplate.Networks {
+		networks = append(networks, networkReference(ctx, dockerClient, attachment.Target, nil))
+	}
+	var diags diag.Diagnostics
+	data.Networks, diags = types.ListValueFrom(ctx, types.StringType, networks)
+	resp.Diagnostics.Append(diags...)
+
+	labels := service.Spec.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	data.Labels, diags = types.MapValueFrom(ctx, types.StringType, labels)
+	resp.Diagnostics.Append(diags...)
 
-	// For the purposes of this example code, hardcoding a response value to
-	// save into the Terraform state.
-	data.Name = types.StringValue("example-service")
-	data.Image = types.StringValue("nginx:latest")
-	data.Replicas = types.Int64Value(3)
+	data.UpdateStatus = nil
+	if service.UpdateStatus != nil {
+		data.UpdateStatus = &ServiceUpdateStatusDataModel{
+			State:       types.StringValue(string(service.UpdateStatus.State)),
+			Message:     types.StringValue(service.UpdateStatus.Message),
+			StartedAt:   formatTimestamp(service.UpdateStatus.StartedAt),
+			CompletedAt: formatTimestamp(service.UpdateStatus.CompletedAt),
+		}
+	}
 
-	// Write logs using the tflog package
-	// Documentation: https://terraform.io/plugin/log
-	tflog.Trace(ctx, "read a data source")
+	tflog.Trace(ctx, "read service data source", map[string]interface{}{
+		"service_id": service.ID,
+	})
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
\ No newline at end of file
+}
+
+// lookupService finds the single service matching the id, name or label
+// filter of the data source configuration.
+func lookupService(ctx context.Context, dockerClient *client.Client, data *ServiceDataSourceModel) (swarm.Service, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if !data.Id.IsNull() {
+		service, _, err := dockerClient.ServiceInspectWithRaw(ctx, data.Id.ValueString(), dockerTypes.ServiceInspectOptions{})
+		if err != nil {
+			diags.AddError(
+				"Error Reading Service",
+				"Could not read service ID "+data.Id.ValueString()+": "+err.Error(),
+			)
+		}
+		return service, diags
+	}
+
+	args := filters.NewArgs()
+	description := ""
+	if !data.Name.IsNull() {
+		args.Add("name", data.Name.ValueString())
+		description = "name " + data.Name.ValueString()
+	} else {
+		labels := map[string]string{}
+		diags.Append(data.LabelFilter.ElementsAs(ctx, &labels, false)...)
+		if diags.HasError() {
+			return swarm.Service{}, diags
+		}
+		selectors := make([]string, 0, len(labels))
+		for key, value := range labels {
+			if value == "" {
+				selectors = append(selectors, key)
+			} else {
+				selectors = append(selectors, key+"="+value)
+			}
+		}
+		sort.Strings(selectors)
+		for _, selector := range selectors {
+			args.Add("label", selector)
+		}
+		description = "labels " + strings.Join(selectors, ", ")
+	}
+
+	services, err := dockerClient.ServiceList(ctx, dockerTypes.ServiceListOptions{Filters: args})
+	if err != nil {
+		diags.AddError(
+			"Error Listing Services",
+			"Could not list services matching "+description+": "+err.Error(),
+		)
+		return swarm.Service{}, diags
+	}
+
+	// The name filter matches on prefix, keep exact matches only
+	if !data.Name.IsNull() {
+		exact := services[:0]
+		for _, service := range services {
+			if service.Spec.Name == data.Name.ValueString() {
+				exact = append(exact, service)
+			}
+		}
+		services = exact
+	}
+
+	switch len(services) {
+	case 0:
+		diags.AddError(
+			"Service Not Found",
+			"No service matches "+description+".",
+		)
+		return swarm.Service{}, diags
+	case 1:
+		return services[0], diags
+	default:
+		diags.AddError(
+			"Multiple Services Found",
+			fmt.Sprintf("%d services match %s, refine the lookup to select a single service.", len(services), description),
+		)
+		return swarm.Service{}, diags
+	}
+}
+
+// formatTimestamp renders an optional timestamp as RFC3339.
+func formatTimestamp(t *time.Time) types.String {
+	if t == nil {
+		return types.StringNull()
+	}
+	return types.StringValue(t.Format(time.RFC3339))
+}
@@ -15,7 +15,11 @@ func TestAccServiceDataSource(t *testing.T) {
 			{
 				Config: testAccServiceDataSourceConfig,
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("data.swarm_service.test", "id", "service-id"),
+					resource.TestCheckResourceAttrPair("data.swarm_service.by_id", "id", "swarm_service.test", "id"),
+					resource.TestCheckResourceAttrPair("data.swarm_service.by_name", "id", "swarm_service.test", "id"),
+					resource.TestCheckResourceAttrPair("data.swarm_service.by_label", "id", "swarm_service.test", "id"),
+					resource.TestCheckResourceAttr("data.swarm_service.by_name", "image", "nginx:latest"),
+					resource.TestCheckResourceAttr("data.swarm_service.by_name", "replicas", "1"),
 				),
 			},
 		},
@@ -23,7 +27,44 @@ func TestAccServiceDataSource(t *testing.T) {
 }
 
 const testAccServiceDataSourceConfig = `
-data "swarm_service" "test" {
-  id = "service-id"
+resource "swarm_service" "test" {
+  name  = "example-data"
+  image = "nginx:latest"
+
+  labels = {
+    team = "platform"
+  }
+
+  node = {
+    host = "unix:///var/run/docker.sock"
+  }
+}
+
+data "swarm_service" "by_id" {
+  id = swarm_service.test.id
+
+  node = {
+    host = "unix:///var/run/docker.sock"
+  }
+}
+
+data "swarm_service" "by_name" {
+  name = swarm_service.test.name
+
+  node = {
+    host = "unix:///var/run/docker.sock"
+  }
+}
+
+data "swarm_service" "by_label" {
+  label_filter = {
+    team = "platform"
+  }
+
+  node = {
+    host = "unix:///var/run/docker.sock"
+  }
+
+  depends_on = [swarm_service.test]
 }
-`
\ No newline at end of file
+`
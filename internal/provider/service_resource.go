@@ -2,37 +2,69 @@ package provider
 
 import (
 	"context"
-	"fmt"
+	"strings"
 
+	dockerTypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sntns/terraform-provider-swarm/internal/docker"
+	"github.com/sntns/terraform-provider-swarm/internal/resources"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &ServiceResource{}
+var _ resource.ResourceWithConfigure = &ServiceResource{}
 var _ resource.ResourceWithImportState = &ServiceResource{}
 
+const (
+	serviceModeReplicated = "replicated"
+	serviceModeGlobal     = "global"
+)
+
 func NewServiceResource() resource.Resource {
 	return &ServiceResource{}
 }
 
 // ServiceResource defines the resource implementation.
 type ServiceResource struct {
-	client *SwarmClient
+	providerData *resources.SwarmProviderData
 }
 
 // ServiceResourceModel describes the resource data model.
 type ServiceResourceModel struct {
-	Id          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Image       types.String `tfsdk:"image"`
-	Replicas    types.Int64  `tfsdk:"replicas"`
-	ConfigurableAttribute types.String `tfsdk:"configurable_attribute"`
+	Id          types.String       `tfsdk:"id"`
+	Node        *docker.TfNode     `tfsdk:"node"`
+	Name        types.String       `tfsdk:"name"`
+	Image       types.String       `tfsdk:"image"`
+	Mode        types.String       `tfsdk:"mode"`
+	Replicas    types.Int64        `tfsdk:"replicas"`
+	Command     types.List         `tfsdk:"command"`
+	Args        types.List         `tfsdk:"args"`
+	Env         types.Map          `tfsdk:"env"`
+	Labels      types.Map          `tfsdk:"labels"`
+	Networks    types.List         `tfsdk:"networks"`
+	Constraints types.List         `tfsdk:"constraints"`
+	Ports       []ServicePortModel `tfsdk:"ports"`
+}
+
+// ServicePortModel describes a port published by the service.
+type ServicePortModel struct {
+	TargetPort    types.Int64  `tfsdk:"target_port"`
+	PublishedPort types.Int64  `tfsdk:"published_port"`
+	Protocol      types.String `tfsdk:"protocol"`
+	PublishMode   types.String `tfsdk:"publish_mode"`
 }
 
 func (r *ServiceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -45,10 +77,7 @@ func (r *ServiceResource) Schema(ctx context.Context, req resource.SchemaRequest
 		MarkdownDescription: "Swarm service resource",
 
 		Attributes: map[string]schema.Attribute{
-			"configurable_attribute": schema.StringAttribute{
-				MarkdownDescription: "Service configurable attribute",
-				Optional:            true,
-			},
+			"node": docker.NodeSchema,
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Service identifier",
@@ -59,37 +88,116 @@ func (r *ServiceResource) Schema(ctx context.Context, req resource.SchemaRequest
 			"name": schema.StringAttribute{
 				MarkdownDescription: "Service name",
 				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"image": schema.StringAttribute{
 				MarkdownDescription: "Container image for the service",
 				Required:            true,
 			},
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "Scheduling mode of the service (`replicated` or `global`)",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(serviceModeReplicated),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(serviceModeReplicated, serviceModeGlobal),
+				},
+			},
 			"replicas": schema.Int64Attribute{
-				MarkdownDescription: "Number of service replicas",
+				MarkdownDescription: "Number of service replicas. Ignored in `global` mode",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(1),
+			},
+			"command": schema.ListAttribute{
+				MarkdownDescription: "Command overriding the image entrypoint",
+				ElementType:         types.StringType,
 				Optional:            true,
 			},
+			"args": schema.ListAttribute{
+				MarkdownDescription: "Arguments passed to the command",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"env": schema.MapAttribute{
+				MarkdownDescription: "Environment variables of the service containers",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"labels": schema.MapAttribute{
+				MarkdownDescription: "Labels attached to the service",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"networks": schema.ListAttribute{
+				MarkdownDescription: "Names or IDs of the networks the service is attached to",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"constraints": schema.ListAttribute{
+				MarkdownDescription: "Placement constraints (e.g. `node.role==manager`)",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"ports": schema.ListNestedAttribute{
+				MarkdownDescription: "Ports published by the service",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"target_port": schema.Int64Attribute{
+							MarkdownDescription: "Port inside the container",
+							Required:            true,
+						},
+						"published_port": schema.Int64Attribute{
+							MarkdownDescription: "Port published on the swarm hosts",
+							Optional:            true,
+						},
+						"protocol": schema.StringAttribute{
+							MarkdownDescription: "Port protocol (`tcp`, `udp` or `sctp`)",
+							Optional:            true,
+							Computed:            true,
+							Default:             stringdefault.StaticString(string(swarm.PortConfigProtocolTCP)),
+							Validators: []validator.String{
+								stringvalidator.OneOf(
+									string(swarm.PortConfigProtocolTCP),
+									string(swarm.PortConfigProtocolUDP),
+									string(swarm.PortConfigProtocolSCTP),
+								),
+							},
+						},
+						"publish_mode": schema.StringAttribute{
+							MarkdownDescription: "Publish mode (`ingress` or `host`)",
+							Optional:            true,
+							Computed:            true,
+							Default:             stringdefault.StaticString(string(swarm.PortConfigPublishModeIngress)),
+							Validators: []validator.String{
+								stringvalidator.OneOf(
+									string(swarm.PortConfigPublishModeIngress),
+									string(swarm.PortConfigPublishModeHost),
+								),
+							},
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
+// Services use the connection described by their node block, or the default
+// connection and registry credentials of the provider.
 func (r *ServiceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
-
-	client, ok := req.ProviderData.(*SwarmClient)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *SwarmClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
-
-		return
-	}
-
-	r.client = client
+	providerData, diags := resources.ConfiguredProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	r.providerData = providerData
 }
 
 func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -97,26 +205,44 @@ func (r *ServiceResource) Create(ctx context.Context, req resource.CreateRequest
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dockerClient, err := r.providerData.NewClient(data.Node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client",
+			"An unexpected error occurred when creating the Docker client. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
 
+	spec, diags := expandServiceSpec(ctx, &data)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := r.client.CreateService(...)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create service, got error: %s", err))
-	//     return
-	// }
+	created, err := dockerClient.ServiceCreate(ctx, spec, dockerTypes.ServiceCreateOptions{
+		EncodedRegistryAuth: r.registryAuth(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating service",
+			"Could not create service "+data.Name.ValueString()+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	for _, warning := range created.Warnings {
+		resp.Diagnostics.AddWarning("Service Create Warning", warning)
+	}
 
-	// For the purposes of this example code, hardcoding a response value to
-	// save into the Terraform state.
-	data.Id = types.StringValue("service-id")
+	tflog.Trace(ctx, "created service", map[string]interface{}{
+		"service_id": created.ID,
+	})
 
-	// Write logs using the tflog package
-	// Documentation: https://terraform.io/plugin/log
-	tflog.Trace(ctx, "created a resource")
+	data.Id = types.StringValue(created.ID)
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -127,18 +253,37 @@ func (r *ServiceResource) Read(ctx context.Context, req resource.ReadRequest, re
 
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := r.client.ReadService(...)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read service, got error: %s", err))
-	//     return
-	// }
+	dockerClient, err := r.providerData.NewClient(data.Node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client in Read",
+			"An unexpected error occurred when creating the Docker client in Read. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	service, _, err := dockerClient.ServiceInspectWithRaw(ctx, data.Id.ValueString(), dockerTypes.ServiceInspectOptions{})
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			// Service was removed outside of Terraform
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error Reading Service",
+			"Could not read service ID "+data.Id.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(flattenServiceSpec(ctx, dockerClient, service, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -149,18 +294,53 @@ func (r *ServiceResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dockerClient, err := r.providerData.NewClient(data.Node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client in Update",
+			"An unexpected error occurred when creating the Docker client in Update. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
 
+	spec, diags := expandServiceSpec(ctx, &data)
+	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := r.client.UpdateService(...)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update service, got error: %s", err))
-	//     return
-	// }
+	// The update must carry the current version index of the service
+	service, _, err := dockerClient.ServiceInspectWithRaw(ctx, data.Id.ValueString(), dockerTypes.ServiceInspectOptions{})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Service",
+			"Could not read service ID "+data.Id.ValueString()+" before update: "+err.Error(),
+		)
+		return
+	}
+
+	updated, err := dockerClient.ServiceUpdate(ctx, service.ID, service.Version, spec, dockerTypes.ServiceUpdateOptions{
+		EncodedRegistryAuth: r.registryAuth(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating service",
+			"Could not update service ID "+data.Id.ValueString()+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	for _, warning := range updated.Warnings {
+		resp.Diagnostics.AddWarning("Service Update Warning", warning)
+	}
+
+	tflog.Trace(ctx, "updated service", map[string]interface{}{
+		"service_id": service.ID,
+		"version":    service.Version.Index,
+	})
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -171,25 +351,224 @@ func (r *ServiceResource) Delete(ctx context.Context, req resource.DeleteRequest
 
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := r.client.DeleteService(...)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete service, got error: %s", err))
-	//     return
-	// }
+	dockerClient, err := r.providerData.NewClient(data.Node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client in Delete",
+			"An unexpected error occurred when creating the Docker client in Delete. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	err = dockerClient.ServiceRemove(ctx, data.Id.ValueString())
+	if err != nil && !client.IsErrNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error Deleting Service",
+			"Could not delete service ID "+data.Id.ValueString()+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "deleted service")
 }
 
 func (r *ServiceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-// SwarmClient is a placeholder for the actual Swarm API client
-type SwarmClient struct {
-	// Add client configuration fields here
-}
\ No newline at end of file
+// registryAuth returns the encoded registry credentials of the provider, if any.
+func (r *ServiceResource) registryAuth() string {
+	if r.providerData == nil {
+		return ""
+	}
+	return r.providerData.RegistryAuth
+}
+
+// expandServiceSpec builds the swarm service spec from the resource model.
+func expandServiceSpec(ctx context.Context, data *ServiceResourceModel) (swarm.ServiceSpec, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	spec := swarm.ServiceSpec{
+		Annotations: swarm.Annotations{
+			Name: data.Name.ValueString(),
+		},
+		TaskTemplate: swarm.TaskSpec{
+			ContainerSpec: &swarm.ContainerSpec{
+				Image: data.Image.ValueString(),
+			},
+		},
+	}
+
+	if data.Mode.ValueString() == serviceModeGlobal {
+		spec.Mode.Global = &swarm.GlobalService{}
+	} else {
+		replicas := uint64(data.Replicas.ValueInt64())
+		spec.Mode.Replicated = &swarm.ReplicatedService{Replicas: &replicas}
+	}
+
+	diags.Append(data.Command.ElementsAs(ctx, &spec.TaskTemplate.ContainerSpec.Command, false)...)
+	diags.Append(data.Args.ElementsAs(ctx, &spec.TaskTemplate.ContainerSpec.Args, false)...)
+	diags.Append(data.Labels.ElementsAs(ctx, &spec.Labels, false)...)
+
+	env := map[string]string{}
+	diags.Append(data.Env.ElementsAs(ctx, &env, false)...)
+	for key, value := range env {
+		spec.TaskTemplate.ContainerSpec.Env = append(spec.TaskTemplate.ContainerSpec.Env, key+"="+value)
+	}
+
+	var networks []string
+	diags.Append(data.Networks.ElementsAs(ctx, &networks, false)...)
+	for _, network := range networks {
+		spec.TaskTemplate.Networks = append(spec.TaskTemplate.Networks, swarm.NetworkAttachmentConfig{Target: network})
+	}
+
+	var constraints []string
+	diags.Append(data.Constraints.ElementsAs(ctx, &constraints, false)...)
+	if len(constraints) > 0 {
+		spec.TaskTemplate.Placement = &swarm.Placement{Constraints: constraints}
+	}
+
+	if len(data.Ports) > 0 {
+		spec.EndpointSpec = &swarm.EndpointSpec{}
+		for _, port := range data.Ports {
+			spec.EndpointSpec.Ports = append(spec.EndpointSpec.Ports, swarm.PortConfig{
+				TargetPort:    uint32(port.TargetPort.ValueInt64()),
+				PublishedPort: uint32(port.PublishedPort.ValueInt64()),
+				Protocol:      swarm.PortConfigProtocol(port.Protocol.ValueString()),
+				PublishMode:   swarm.PortConfigPublishMode(port.PublishMode.ValueString()),
+			})
+		}
+	}
+
+	return spec, diags
+}
+
+// flattenServiceSpec refreshes the resource model from the live service so
+// that changes made outside of Terraform show up as drift.
+func flattenServiceSpec(ctx context.Context, dockerClient *client.Client, service swarm.Service, data *ServiceResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	spec := service.Spec
+
+	data.Id = types.StringValue(service.ID)
+	data.Name = types.StringValue(spec.Name)
+
+	switch {
+	case spec.Mode.Global != nil:
+		data.Mode = types.StringValue(serviceModeGlobal)
+	case spec.Mode.Replicated != nil:
+		data.Mode = types.StringValue(serviceModeReplicated)
+		if spec.Mode.Replicated.Replicas != nil {
+			data.Replicas = types.Int64Value(int64(*spec.Mode.Replicated.Replicas))
+		}
+	}
+	if data.Replicas.IsNull() {
+		data.Replicas = types.Int64Value(1)
+	}
+
+	containerSpec := spec.TaskTemplate.ContainerSpec
+	if containerSpec == nil {
+		containerSpec = &swarm.ContainerSpec{}
+	}
+	data.Image = types.StringValue(containerSpec.Image)
+
+	var d diag.Diagnostics
+	data.Command, d = flattenStringList(ctx, containerSpec.Command, data.Command)
+	diags.Append(d...)
+	data.Args, d = flattenStringList(ctx, containerSpec.Args, data.Args)
+	diags.Append(d...)
+	data.Labels, d = flattenStringMap(ctx, spec.Labels, data.Labels)
+	diags.Append(d...)
+
+	env := map[string]string{}
+	for _, entry := range containerSpec.Env {
+		key, value, _ := strings.Cut(entry, "=")
+		env[key] = value
+	}
+	data.Env, d = flattenStringMap(ctx, env, data.Env)
+	diags.Append(d...)
+
+	var constraints []string
+	if spec.TaskTemplate.Placement != nil {
+		constraints = spec.TaskTemplate.Placement.Constraints
+	}
+	data.Constraints, d = flattenStringList(ctx, constraints, data.Constraints)
+	diags.Append(d...)
+
+	// The daemon stores network IDs, map them back to the names used in
+	// the configuration when possible.
+	var configured []string
+	diags.Append(data.Networks.ElementsAs(ctx, &configured, false)...)
+	networks := make([]string, 0, len(spec.TaskTemplate.Networks))
+	for _, attachment := range spec.TaskTemplate.Networks {
+		networks = append(networks, networkReference(ctx, dockerClient, attachment.Target, configured))
+	}
+	data.Networks, d = flattenStringList(ctx, networks, data.Networks)
+	diags.Append(d...)
+
+	data.Ports = nil
+	if spec.EndpointSpec != nil {
+		for _, port := range spec.EndpointSpec.Ports {
+			model := ServicePortModel{
+				TargetPort:    types.Int64Value(int64(port.TargetPort)),
+				PublishedPort: types.Int64Null(),
+				Protocol:      types.StringValue(string(port.Protocol)),
+				PublishMode:   types.StringValue(string(port.PublishMode)),
+			}
+			if port.PublishedPort != 0 {
+				model.PublishedPort = types.Int64Value(int64(port.PublishedPort))
+			}
+			data.Ports = append(data.Ports, model)
+		}
+	}
+
+	return diags
+}
+
+// networkReference returns the configured reference (name or ID) matching the
+// network attached to the service, falling back to the network name.
+func networkReference(ctx context.Context, dockerClient *client.Client, target string, configured []string) string {
+	for _, ref := range configured {
+		if ref == target {
+			return ref
+		}
+	}
+	network, err := dockerClient.NetworkInspect(ctx, target, dockerTypes.NetworkInspectOptions{})
+	if err != nil {
+		tflog.Debug(ctx, "Could not inspect service network", map[string]interface{}{
+			"network": target,
+			"error":   err.Error(),
+		})
+		return target
+	}
+	for _, ref := range configured {
+		if ref == network.Name || ref == network.ID {
+			return ref
+		}
+	}
+	return network.Name
+}
+
+// flattenStringList converts values into a list, keeping a null prior value
+// when there is nothing to report.
+func flattenStringList(ctx context.Context, values []string, prior types.List) (types.List, diag.Diagnostics) {
+	if len(values) == 0 && prior.IsNull() {
+		return types.ListNull(types.StringType), nil
+	}
+	if values == nil {
+		values = []string{}
+	}
+	return types.ListValueFrom(ctx, types.StringType, values)
+}
+
+// flattenStringMap converts values into a map, keeping a null prior value
+// when there is nothing to report.
+func flattenStringMap(ctx context.Context, values map[string]string, prior types.Map) (types.Map, diag.Diagnostics) {
+	if len(values) == 0 && prior.IsNull() {
+		return types.MapNull(types.StringType), nil
+	}
+	return types.MapValueFrom(ctx, types.StringType, values)
+}
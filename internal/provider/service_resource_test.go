@@ -1,10 +1,13 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccServiceResource(t *testing.T) {
@@ -14,30 +17,28 @@ func TestAccServiceResource(t *testing.T) {
 		Steps: []resource.TestStep{
 			// Create and Read testing
 			{
-				Config: testAccServiceResourceConfig("example"),
+				Config: testAccServiceResourceConfig(1),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("swarm_service.test", "name", "example"),
 					resource.TestCheckResourceAttr("swarm_service.test", "image", "nginx:latest"),
-					resource.TestCheckResourceAttr("swarm_service.test", "configurable_attribute", "example"),
+					resource.TestCheckResourceAttr("swarm_service.test", "mode", "replicated"),
+					resource.TestCheckResourceAttr("swarm_service.test", "replicas", "1"),
 					resource.TestCheckResourceAttrSet("swarm_service.test", "id"),
 				),
 			},
-			// ImportState testing
+			// ImportState testing, through the default connection of the
+			// provider since the import ID only carries the service ID
 			{
-				ResourceName:      "swarm_service.test",
-				ImportState:       true,
-				ImportStateVerify: true,
-				// This is not normally necessary, but is here because this
-				// example code does not have an actual upstream service.
-				// Once the Create method is able to refresh information from
-				// the upstream service, this can be removed.
-				ImportStateVerifyIgnore: []string{"configurable_attribute"},
+				ResourceName:            "swarm_service.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"node"},
 			},
 			// Update and Read testing
 			{
-				Config: testAccServiceResourceConfig("updated"),
+				Config: testAccServiceResourceConfig(2),
 				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestCheckResourceAttr("swarm_service.test", "configurable_attribute", "updated"),
+					resource.TestCheckResourceAttr("swarm_service.test", "replicas", "2"),
 				),
 			},
 			// Delete testing automatically occurs in TestCase
@@ -45,13 +46,58 @@ func TestAccServiceResource(t *testing.T) {
 	})
 }
 
-func testAccServiceResourceConfig(configurableAttribute string) string {
+func testAccServiceResourceConfig(replicas int) string {
 	return fmt.Sprintf(`
 resource "swarm_service" "test" {
   name     = "example"
   image    = "nginx:latest"
-  replicas = 1
-  configurable_attribute = %[1]q
+  replicas = %[1]d
+
+  node = {
+    host = "unix:///var/run/docker.sock"
+  }
+}
+`, replicas)
+}
+
+func TestExpandServiceSpec(t *testing.T) {
+	ctx := context.Background()
+	env, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"FOO": "bar"})
+	constraints, _ := types.ListValueFrom(ctx, types.StringType, []string{"node.role==manager"})
+
+	data := ServiceResourceModel{
+		Name:        types.StringValue("web"),
+		Image:       types.StringValue("nginx:latest"),
+		Mode:        types.StringValue(serviceModeReplicated),
+		Replicas:    types.Int64Value(3),
+		Command:     types.ListNull(types.StringType),
+		Args:        types.ListNull(types.StringType),
+		Env:         env,
+		Labels:      types.MapNull(types.StringType),
+		Networks:    types.ListNull(types.StringType),
+		Constraints: constraints,
+		Ports: []ServicePortModel{{
+			TargetPort:    types.Int64Value(80),
+			PublishedPort: types.Int64Value(8080),
+			Protocol:      types.StringValue("tcp"),
+			PublishMode:   types.StringValue("ingress"),
+		}},
+	}
+
+	spec, diags := expandServiceSpec(ctx, &data)
+	assert.False(t, diags.HasError())
+	assert.Equal(t, "web", spec.Name)
+	assert.Equal(t, "nginx:latest", spec.TaskTemplate.ContainerSpec.Image)
+	assert.Equal(t, []string{"FOO=bar"}, spec.TaskTemplate.ContainerSpec.Env)
+	assert.Equal(t, []string{"node.role==manager"}, spec.TaskTemplate.Placement.Constraints)
+	assert.NotNil(t, spec.Mode.Replicated)
+	assert.Equal(t, uint64(3), *spec.Mode.Replicated.Replicas)
+	assert.Len(t, spec.EndpointSpec.Ports, 1)
+	assert.Equal(t, uint32(8080), spec.EndpointSpec.Ports[0].PublishedPort)
+
+	data.Mode = types.StringValue(serviceModeGlobal)
+	spec, diags = expandServiceSpec(ctx, &data)
+	assert.False(t, diags.HasError())
+	assert.NotNil(t, spec.Mode.Global)
+	assert.Nil(t, spec.Mode.Replicated)
 }
-`, configurableAttribute)
-}
\ No newline at end of file
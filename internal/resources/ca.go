@@ -0,0 +1,225 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// swarmExternalCAModel maps an external certificate authority of the swarm.
+type swarmExternalCAModel struct {
+	Protocol tfTypes.String `tfsdk:"protocol"`
+	URL      tfTypes.String `tfsdk:"url"`
+	Options  tfTypes.Map    `tfsdk:"options"`
+	CACert   tfTypes.String `tfsdk:"ca_cert"`
+}
+
+// expandCAConfig applies the certificate settings set in the model on top of
+// spec. The signing CA material is handled by the callers since it must only
+// be sent when it changes.
+func expandCAConfig(ctx context.Context, model *swarmInitResourceModel, spec *swarm.Spec) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if isKnown(model.NodeCertExpiry) {
+		expiry, err := time.ParseDuration(model.NodeCertExpiry.ValueString())
+		if err != nil {
+			diags.AddError(
+				"Invalid node_cert_expiry",
+				"Could not parse node_cert_expiry "+model.NodeCertExpiry.ValueString()+" as a duration: "+err.Error(),
+			)
+			return diags
+		}
+		spec.CAConfig.NodeCertExpiry = expiry
+	}
+
+	// Without external CAs in the configuration the swarm issues the node
+	// certificates itself
+	if model.ExternalCAs == nil {
+		spec.CAConfig.ExternalCAs = nil
+		return diags
+	}
+	externalCAs := make([]*swarm.ExternalCA, 0, len(model.ExternalCAs))
+	for _, externalCA := range model.ExternalCAs {
+		options := map[string]string{}
+		if isKnown(externalCA.Options) {
+			diags.Append(externalCA.Options.ElementsAs(ctx, &options, false)...)
+			if diags.HasError() {
+				return diags
+			}
+		}
+		externalCAs = append(externalCAs, &swarm.ExternalCA{
+			Protocol: swarm.ExternalCAProtocol(externalCA.Protocol.ValueString()),
+			URL:      externalCA.URL.ValueString(),
+			Options:  options,
+			CACert:   externalCA.CACert.ValueString(),
+		})
+	}
+	spec.CAConfig.ExternalCAs = externalCAs
+
+	return diags
+}
+
+// flattenCAConfig copies the certificate settings of spec into the model.
+// The external CAs are only reported when configured or set on the cluster,
+// and the signing CA material, which Docker never returns, is left untouched.
+func flattenCAConfig(ctx context.Context, spec swarm.Spec, model *swarmInitResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	model.NodeCertExpiry = flattenDuration(model.NodeCertExpiry, spec.CAConfig.NodeCertExpiry)
+
+	if model.ExternalCAs == nil && len(spec.CAConfig.ExternalCAs) == 0 {
+		return diags
+	}
+	prior := model.ExternalCAs
+	model.ExternalCAs = make([]swarmExternalCAModel, 0, len(spec.CAConfig.ExternalCAs))
+	for i, externalCA := range spec.CAConfig.ExternalCAs {
+		var configured *swarmExternalCAModel
+		if i < len(prior) {
+			configured = &prior[i]
+		}
+
+		flattened := swarmExternalCAModel{
+			Protocol: tfTypes.StringValue(string(externalCA.Protocol)),
+			URL:      tfTypes.StringValue(externalCA.URL),
+			Options:  tfTypes.MapNull(tfTypes.StringType),
+			CACert:   tfTypes.StringNull(),
+		}
+		if len(externalCA.Options) > 0 || (configured != nil && !configured.Options.IsNull()) {
+			var optionDiags diag.Diagnostics
+			flattened.Options, optionDiags = tfTypes.MapValueFrom(ctx, tfTypes.StringType, externalCA.Options)
+			diags.Append(optionDiags...)
+		}
+		// Keep the configured PEM when it only differs by surrounding whitespace
+		if externalCA.CACert != "" {
+			flattened.CACert = tfTypes.StringValue(externalCA.CACert)
+			if configured != nil && strings.TrimSpace(configured.CACert.ValueString()) == strings.TrimSpace(externalCA.CACert) {
+				flattened.CACert = configured.CACert
+			}
+		}
+		model.ExternalCAs = append(model.ExternalCAs, flattened)
+	}
+
+	return diags
+}
+
+// flattenDuration returns the duration as a string, keeping the configured
+// spelling of an equivalent duration ("1m" vs "1m0s").
+func flattenDuration(configured tfTypes.String, actual time.Duration) tfTypes.String {
+	if isKnown(configured) {
+		if duration, err := time.ParseDuration(configured.ValueString()); err == nil && duration == actual {
+			return configured
+		}
+	}
+	return tfTypes.StringValue(actual.String())
+}
+
+// swarmCARotationModel maps the root CA rotation settings of the swarm.
+type swarmCARotationModel struct {
+	Trigger tfTypes.String `tfsdk:"trigger"`
+	Timeout tfTypes.String `tfsdk:"timeout"`
+}
+
+// defaultCARotationTimeout bounds the wait for the nodes to trust a new root CA.
+const defaultCARotationTimeout = "10m"
+
+// waitForCARotation waits until the root CA rotation is over and every node
+// that is not down trusts the root CA of the swarm.
+func waitForCARotation(ctx context.Context, dockerClient *client.Client, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	deadline := time.Now().Add(timeout)
+	for {
+		swarmInfo, err := dockerClient.SwarmInspect(ctx)
+		if err != nil {
+			diags.AddError(
+				"Error inspecting swarm",
+				"Could not inspect swarm during the root CA rotation, unexpected error: "+err.Error(),
+			)
+			return diags
+		}
+		nodes, err := dockerClient.NodeList(ctx, types.NodeListOptions{})
+		if err != nil {
+			diags.AddError(
+				"Error listing nodes",
+				"Could not list the swarm nodes during the root CA rotation, unexpected error: "+err.Error(),
+			)
+			return diags
+		}
+
+		pending, down := pendingCARotation(nodes, swarmInfo.TLSInfo.TrustRoot)
+		if !swarmInfo.RootRotationInProgress && len(pending) == 0 {
+			summary := fmt.Sprintf("All %d reachable node(s) of swarm %s trust the new root CA.", len(nodes)-len(down), swarmInfo.ID)
+			if len(down) > 0 {
+				summary += " Nodes down during the rotation must be rejoined: " + strings.Join(down, ", ") + "."
+			}
+			diags.AddWarning("Root CA Rotation Completed", summary)
+			return diags
+		}
+		if time.Now().After(deadline) {
+			diags.AddError(
+				"Timeout Rotating Root CA",
+				fmt.Sprintf("The root CA rotation of swarm %s did not complete after %s. Nodes not trusting the new root CA yet: %s.",
+					swarmInfo.ID, timeout, strings.Join(pending, ", ")),
+			)
+			return diags
+		}
+		tflog.Debug(ctx, "waiting for root CA rotation", map[string]interface{}{
+			"in_progress": swarmInfo.RootRotationInProgress,
+			"pending":     len(pending),
+		})
+
+		select {
+		case <-ctx.Done():
+			diags.AddError(
+				"Root CA Rotation Interrupted",
+				"Stopped waiting for the root CA rotation: "+ctx.Err().Error(),
+			)
+			return diags
+		case <-time.After(nodePollInterval):
+		}
+	}
+}
+
+// pendingCARotation returns the nodes that do not trust the root CA yet, and
+// the nodes that are down and will not converge until they are back.
+func pendingCARotation(nodes []swarm.Node, trustRoot string) (pending []string, down []string) {
+	for _, node := range nodes {
+		name := node.ID
+		if node.Description.Hostname != "" {
+			name = node.Description.Hostname + " (" + node.ID + ")"
+		}
+		if node.Status.State == swarm.NodeStateDown {
+			down = append(down, name)
+			continue
+		}
+		if strings.TrimSpace(node.Description.TLSInfo.TrustRoot) != strings.TrimSpace(trustRoot) {
+			pending = append(pending, name)
+		}
+	}
+	return pending, down
+}
+
+// caRotationTrigger returns the configured rotation trigger, null when the
+// ca_rotation block is not set.
+func caRotationTrigger(rotation *swarmCARotationModel) tfTypes.String {
+	if rotation == nil {
+		return tfTypes.StringNull()
+	}
+	return rotation.Trigger
+}
+
+// caRotationTimeout returns the configured rotation timeout or the default one.
+func caRotationTimeout(rotation *swarmCARotationModel) string {
+	if rotation == nil || !isKnown(rotation.Timeout) {
+		return defaultCARotationTimeout
+	}
+	return rotation.Timeout.ValueString()
+}
@@ -0,0 +1,117 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandCAConfig(t *testing.T) {
+	options, _ := tfTypes.MapValueFrom(context.Background(), tfTypes.StringType, map[string]string{"profile": "node"})
+	model := swarmInitResourceModel{
+		NodeCertExpiry: tfTypes.StringValue("168h"),
+		ExternalCAs: []swarmExternalCAModel{
+			{
+				Protocol: tfTypes.StringValue("cfssl"),
+				URL:      tfTypes.StringValue("https://ca.example.com/api/v1/cfssl/sign"),
+				Options:  options,
+				CACert:   tfTypes.StringNull(),
+			},
+		},
+	}
+	spec := swarm.Spec{}
+
+	diags := expandCAConfig(context.Background(), &model, &spec)
+
+	assert.False(t, diags.HasError())
+	assert.Equal(t, 168*time.Hour, spec.CAConfig.NodeCertExpiry)
+	assert.Len(t, spec.CAConfig.ExternalCAs, 1)
+	assert.Equal(t, swarm.ExternalCAProtocolCFSSL, spec.CAConfig.ExternalCAs[0].Protocol)
+	assert.Equal(t, "node", spec.CAConfig.ExternalCAs[0].Options["profile"])
+
+	// Removing the external CAs from the configuration removes them from the swarm
+	model.ExternalCAs = nil
+	diags = expandCAConfig(context.Background(), &model, &spec)
+	assert.False(t, diags.HasError())
+	assert.Nil(t, spec.CAConfig.ExternalCAs)
+}
+
+func TestFlattenCAConfig(t *testing.T) {
+	spec := swarm.Spec{
+		CAConfig: swarm.CAConfig{
+			NodeCertExpiry: 168 * time.Hour,
+			ExternalCAs: []*swarm.ExternalCA{
+				{
+					Protocol: swarm.ExternalCAProtocolCFSSL,
+					URL:      "https://ca.example.com/api/v1/cfssl/sign",
+					CACert:   "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----",
+				},
+			},
+		},
+	}
+	model := swarmInitResourceModel{
+		NodeCertExpiry: tfTypes.StringValue("7d"),
+		ExternalCAs: []swarmExternalCAModel{
+			{
+				Protocol: tfTypes.StringValue("cfssl"),
+				URL:      tfTypes.StringValue("https://ca.example.com/api/v1/cfssl/sign"),
+				Options:  tfTypes.MapNull(tfTypes.StringType),
+				CACert:   tfTypes.StringValue("-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"),
+			},
+		},
+	}
+
+	diags := flattenCAConfig(context.Background(), spec, &model)
+
+	assert.False(t, diags.HasError())
+	assert.Equal(t, "168h0m0s", model.NodeCertExpiry.ValueString())
+	assert.Len(t, model.ExternalCAs, 1)
+	assert.True(t, model.ExternalCAs[0].Options.IsNull())
+	// The configured PEM is kept when it only differs by a trailing newline
+	assert.Equal(t, "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n", model.ExternalCAs[0].CACert.ValueString())
+
+	// No external CA configured nor set on the cluster stays null
+	spec.CAConfig.ExternalCAs = nil
+	model.ExternalCAs = nil
+	diags = flattenCAConfig(context.Background(), spec, &model)
+	assert.False(t, diags.HasError())
+	assert.Nil(t, model.ExternalCAs)
+}
+
+func TestPendingCARotation(t *testing.T) {
+	root := "-----BEGIN CERTIFICATE-----\nNEW\n-----END CERTIFICATE-----\n"
+	old := "-----BEGIN CERTIFICATE-----\nOLD\n-----END CERTIFICATE-----\n"
+	node := func(id, hostname, trustRoot string, state swarm.NodeState) swarm.Node {
+		return swarm.Node{
+			ID:          id,
+			Description: swarm.NodeDescription{Hostname: hostname, TLSInfo: swarm.TLSInfo{TrustRoot: trustRoot}},
+			Status:      swarm.NodeStatus{State: state},
+		}
+	}
+	nodes := []swarm.Node{
+		node("n1", "manager-1", root, swarm.NodeStateReady),
+		node("n2", "worker-1", old, swarm.NodeStateReady),
+		node("n3", "", old, swarm.NodeStateDown),
+	}
+
+	pending, down := pendingCARotation(nodes, root)
+
+	assert.Equal(t, []string{"worker-1 (n2)"}, pending)
+	assert.Equal(t, []string{"n3"}, down)
+}
+
+func TestCARotationSettings(t *testing.T) {
+	assert.True(t, caRotationTrigger(nil).IsNull())
+	assert.Equal(t, defaultCARotationTimeout, caRotationTimeout(nil))
+
+	rotation := &swarmCARotationModel{
+		Trigger: tfTypes.StringValue("2026-10"),
+		Timeout: tfTypes.StringValue("30m"),
+	}
+	assert.Equal(t, "2026-10", caRotationTrigger(rotation).ValueString())
+	assert.Equal(t, "30m", caRotationTimeout(rotation))
+}
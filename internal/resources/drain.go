@@ -0,0 +1,267 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// destroyStrategyLeave leaves the swarm right away, forcing it if needed.
+	destroyStrategyLeave = "leave"
+	// destroyStrategyDrain drains the node and waits for its tasks to stop
+	// and run on other nodes before leaving the swarm.
+	destroyStrategyDrain = "drain"
+
+	// defaultDrainTimeout bounds the wait for the tasks of a drained node.
+	defaultDrainTimeout = "5m"
+)
+
+// nodePollInterval is the delay between two inspections of a node being
+// drained or removed.
+var nodePollInterval = 2 * time.Second
+
+// taskSlot identifies a replica of a replicated service.
+type taskSlot struct {
+	serviceID string
+	slot      int
+}
+
+// drainNode sets the availability of the node to drain and waits until no
+// task is running on it anymore, then until the replicas it ran are running
+// on other nodes.
+func drainNode(ctx context.Context, managerClient *client.Client, nodeID string, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	args := filters.NewArgs(filters.Arg("node", nodeID))
+	tasks, err := managerClient.TaskList(ctx, types.TaskListOptions{Filters: args})
+	if err != nil {
+		diags.AddError(
+			"Error listing node tasks",
+			"Could not list the tasks of node "+nodeID+" before draining it, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	displaced := replicaSlots(tasks)
+
+	node, _, err := managerClient.NodeInspectWithRaw(ctx, nodeID)
+	if err != nil {
+		diags.AddError(
+			"Error inspecting node",
+			"Could not inspect node "+nodeID+" before draining it, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	if node.Spec.Availability != swarm.NodeAvailabilityDrain {
+		spec := node.Spec
+		spec.Availability = swarm.NodeAvailabilityDrain
+		err = managerClient.NodeUpdate(ctx, nodeID, node.Version, spec)
+		if err != nil {
+			diags.AddError(
+				"Error draining node",
+				"Could not set the availability of node "+nodeID+" to drain, unexpected error: "+err.Error(),
+			)
+			return diags
+		}
+		tflog.Trace(ctx, "drained node", map[string]interface{}{
+			"node_id": nodeID,
+		})
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		tasks, err := managerClient.TaskList(ctx, types.TaskListOptions{Filters: args})
+		if err != nil {
+			diags.AddError(
+				"Error listing node tasks",
+				"Could not list the tasks of node "+nodeID+", unexpected error: "+err.Error(),
+			)
+			return diags
+		}
+		active := activeTaskCount(tasks)
+		if active == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			diags.AddError(
+				"Timeout Draining Node",
+				fmt.Sprintf("Node %s still runs %d task(s) after %s. Increase drain_timeout or use the %q destroy strategy.",
+					nodeID, active, timeout, destroyStrategyLeave),
+			)
+			return diags
+		}
+		tflog.Debug(ctx, "waiting for node tasks to stop", map[string]interface{}{
+			"node_id": nodeID,
+			"tasks":   active,
+		})
+
+		select {
+		case <-ctx.Done():
+			diags.AddError(
+				"Draining Node Interrupted",
+				"Stopped waiting for the tasks of node "+nodeID+": "+ctx.Err().Error(),
+			)
+			return diags
+		case <-time.After(nodePollInterval):
+		}
+	}
+
+	if len(displaced) == 0 {
+		return diags
+	}
+	serviceArgs := filters.NewArgs(filters.Arg("desired-state", string(swarm.TaskStateRunning)))
+	for slot := range displaced {
+		serviceArgs.Add("service", slot.serviceID)
+	}
+	for {
+		tasks, err := managerClient.TaskList(ctx, types.TaskListOptions{Filters: serviceArgs})
+		if err != nil {
+			diags.AddError(
+				"Error listing service tasks",
+				"Could not list the tasks rescheduled from node "+nodeID+", unexpected error: "+err.Error(),
+			)
+			return diags
+		}
+		pending := pendingReplicaCount(displaced, tasks, nodeID)
+		if pending == 0 {
+			return diags
+		}
+		if time.Now().After(deadline) {
+			diags.AddError(
+				"Timeout Draining Node",
+				fmt.Sprintf("%d task(s) of node %s are still not running on another node after %s, e.g. because of placement constraints or missing resources. "+
+					"Increase drain_timeout or use the %q destroy strategy.",
+					pending, nodeID, timeout, destroyStrategyLeave),
+			)
+			return diags
+		}
+		tflog.Debug(ctx, "waiting for node tasks to be rescheduled", map[string]interface{}{
+			"node_id": nodeID,
+			"tasks":   pending,
+		})
+
+		select {
+		case <-ctx.Done():
+			diags.AddError(
+				"Draining Node Interrupted",
+				"Stopped waiting for the tasks of node "+nodeID+" to be rescheduled: "+ctx.Err().Error(),
+			)
+			return diags
+		case <-time.After(nodePollInterval):
+		}
+	}
+}
+
+// replicaSlots returns the replicas of replicated services that the tasks
+// run. Global services have no slot and are not rescheduled elsewhere.
+func replicaSlots(tasks []swarm.Task) map[taskSlot]bool {
+	slots := map[taskSlot]bool{}
+	for _, task := range tasks {
+		if task.Slot == 0 || task.DesiredState != swarm.TaskStateRunning {
+			continue
+		}
+		slots[taskSlot{serviceID: task.ServiceID, slot: task.Slot}] = true
+	}
+	return slots
+}
+
+// pendingReplicaCount returns the number of displaced replicas that do not
+// run on another node than the drained one yet. A replica no task should run
+// anymore, e.g. after its service was scaled down or removed, is not pending.
+func pendingReplicaCount(displaced map[taskSlot]bool, tasks []swarm.Task, nodeID string) int {
+	wanted := map[taskSlot]bool{}
+	running := map[taskSlot]bool{}
+	for _, task := range tasks {
+		if task.DesiredState != swarm.TaskStateRunning {
+			continue
+		}
+		slot := taskSlot{serviceID: task.ServiceID, slot: task.Slot}
+		wanted[slot] = true
+		if task.NodeID != nodeID && task.Status.State == swarm.TaskStateRunning {
+			running[slot] = true
+		}
+	}
+	count := 0
+	for slot := range displaced {
+		if wanted[slot] && !running[slot] {
+			count++
+		}
+	}
+	return count
+}
+
+// activeTaskCount returns the number of tasks that are not in a terminal state.
+func activeTaskCount(tasks []swarm.Task) int {
+	count := 0
+	for _, task := range tasks {
+		switch task.Status.State {
+		case swarm.TaskStateComplete, swarm.TaskStateShutdown, swarm.TaskStateFailed,
+			swarm.TaskStateRejected, swarm.TaskStateRemove, swarm.TaskStateOrphaned:
+		default:
+			count++
+		}
+	}
+	return count
+}
+
+// removeNode waits for the managers to see the node as down and removes it
+// from the node list.
+func removeNode(ctx context.Context, managerClient *client.Client, nodeID string, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	deadline := time.Now().Add(timeout)
+	for {
+		node, _, err := managerClient.NodeInspectWithRaw(ctx, nodeID)
+		if err != nil {
+			if client.IsErrNotFound(err) {
+				return diags
+			}
+			diags.AddError(
+				"Error inspecting node",
+				"Could not inspect node "+nodeID+" before removing it, unexpected error: "+err.Error(),
+			)
+			return diags
+		}
+		if node.Status.State == swarm.NodeStateDown {
+			break
+		}
+		if time.Now().After(deadline) {
+			diags.AddError(
+				"Timeout Removing Node",
+				fmt.Sprintf("Node %s is still %s after %s and cannot be removed from the swarm.", nodeID, node.Status.State, timeout),
+			)
+			return diags
+		}
+
+		select {
+		case <-ctx.Done():
+			diags.AddError(
+				"Removing Node Interrupted",
+				"Stopped waiting for node "+nodeID+" to be down: "+ctx.Err().Error(),
+			)
+			return diags
+		case <-time.After(nodePollInterval):
+		}
+	}
+
+	err := managerClient.NodeRemove(ctx, nodeID, types.NodeRemoveOptions{})
+	if err != nil && !client.IsErrNotFound(err) {
+		diags.AddError(
+			"Error removing node",
+			"Could not remove node "+nodeID+" from the swarm, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+
+	tflog.Trace(ctx, "removed node", map[string]interface{}{
+		"node_id": nodeID,
+	})
+	return diags
+}
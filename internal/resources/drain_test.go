@@ -0,0 +1,59 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActiveTaskCount(t *testing.T) {
+	tasks := []swarm.Task{
+		{Status: swarm.TaskStatus{State: swarm.TaskStateRunning}},
+		{Status: swarm.TaskStatus{State: swarm.TaskStateStarting}},
+		{Status: swarm.TaskStatus{State: swarm.TaskStateShutdown}},
+		{Status: swarm.TaskStatus{State: swarm.TaskStateComplete}},
+		{Status: swarm.TaskStatus{State: swarm.TaskStateFailed}},
+	}
+
+	assert.Equal(t, 2, activeTaskCount(tasks))
+	assert.Equal(t, 0, activeTaskCount(nil))
+}
+
+func TestPendingReplicaCount(t *testing.T) {
+	task := func(serviceID string, slot int, nodeID string, state swarm.TaskState) swarm.Task {
+		return swarm.Task{
+			ServiceID:    serviceID,
+			Slot:         slot,
+			NodeID:       nodeID,
+			DesiredState: swarm.TaskStateRunning,
+			Status:       swarm.TaskStatus{State: state},
+		}
+	}
+
+	// web.1 and api.1 ran on the drained node, the global agent has no slot
+	global := task("agent", 0, "n1", swarm.TaskStateRunning)
+	shutdown := task("web", 2, "n1", swarm.TaskStateShutdown)
+	shutdown.DesiredState = swarm.TaskStateShutdown
+	displaced := replicaSlots([]swarm.Task{
+		task("web", 1, "n1", swarm.TaskStateRunning),
+		task("api", 1, "n1", swarm.TaskStateRunning),
+		global,
+		shutdown,
+	})
+	assert.Len(t, displaced, 2)
+
+	// web.1 runs elsewhere, api.1 is pending, e.g. pinned by a constraint
+	tasks := []swarm.Task{
+		task("web", 1, "n2", swarm.TaskStateRunning),
+		task("api", 1, "", swarm.TaskStatePending),
+	}
+	assert.Equal(t, 1, pendingReplicaCount(displaced, tasks, "n1"))
+
+	// api.1 is running on another node
+	tasks[1] = task("api", 1, "n3", swarm.TaskStateRunning)
+	assert.Equal(t, 0, pendingReplicaCount(displaced, tasks, "n1"))
+
+	// api was scaled down and no task should run for its slot anymore
+	assert.Equal(t, 0, pendingReplicaCount(displaced, tasks[:1], "n1"))
+}
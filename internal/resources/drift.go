@@ -0,0 +1,117 @@
+package resources
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// swarmClusterID returns the ID of the swarm the node is part of. Workers do
+// not know it and need a manager connection, it is empty without one.
+func swarmClusterID(ctx context.Context, info swarm.Info, managerClient *client.Client) (string, error) {
+	if info.Cluster != nil {
+		return info.Cluster.ID, nil
+	}
+	if managerClient == nil {
+		return "", nil
+	}
+	swarmInfo, err := managerClient.SwarmInspect(ctx)
+	if err != nil {
+		return "", err
+	}
+	return swarmInfo.ID, nil
+}
+
+// splitAddr splits an address into its host and its port, empty when the
+// address has none.
+func splitAddr(addr string) (string, string) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return strings.Trim(addr, "[]"), ""
+	}
+	return host, port
+}
+
+// addrMoved reports whether the node no longer uses the configured address.
+// Interface names and hostnames cannot be compared and never report a move,
+// nor does a port missing on either side.
+func addrMoved(configured, actual string) bool {
+	if configured == "" || actual == "" {
+		return false
+	}
+	host, port := splitAddr(configured)
+	actualHost, actualPort := splitAddr(actual)
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	if !ip.Equal(net.ParseIP(actualHost)) {
+		return true
+	}
+	return port != "" && actualPort != "" && port != actualPort
+}
+
+// sameAddr reports whether two addresses designate the same one, a port
+// missing on either side standing for the default one.
+func sameAddr(a, b string) bool {
+	host, port := splitAddr(a)
+	otherHost, otherPort := splitAddr(b)
+	if ip := net.ParseIP(host); ip != nil {
+		if !ip.Equal(net.ParseIP(otherHost)) {
+			return false
+		}
+	} else if host != otherHost {
+		return false
+	}
+	return port == "" || otherPort == "" || port == otherPort
+}
+
+// requiresReplaceIfAddrChanged plans a replacement when the configured
+// address differs from the one in the state. An address missing from the
+// state, as after an import, is not compared: the refresh following the
+// apply reports the node as moved if it does not use the configured one.
+func requiresReplaceIfAddrChanged() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			if !isKnown(req.ConfigValue) || !isKnown(req.StateValue) {
+				return
+			}
+			resp.RequiresReplace = !sameAddr(req.ConfigValue.ValueString(), req.StateValue.ValueString())
+		},
+		"Changing the configured address forces a replacement.",
+		"Changing the configured address forces a replacement.",
+	)
+}
+
+// refreshAddr returns the live address when the node no longer uses the
+// configured one, so that the difference plans a replacement.
+func refreshAddr(configured tfTypes.String, actual string) tfTypes.String {
+	if !isKnown(configured) || !addrMoved(configured.ValueString(), actual) {
+		return configured
+	}
+	return tfTypes.StringValue(actual)
+}
+
+// refreshListenAddr returns the configured listen address with the live raft
+// port when the node listens on another port. The raft address only carries
+// the listen port when the advertise address does not set one.
+func refreshListenAddr(listen, advertise tfTypes.String, raftAddr string) tfTypes.String {
+	if !isKnown(listen) || raftAddr == "" {
+		return listen
+	}
+	if _, port := splitAddr(advertise.ValueString()); port != "" {
+		return listen
+	}
+	host, port := splitAddr(listen.ValueString())
+	_, actualPort := splitAddr(raftAddr)
+	if port == "" || actualPort == "" || port == actualPort {
+		return listen
+	}
+	return tfTypes.StringValue(net.JoinHostPort(host, actualPort))
+}
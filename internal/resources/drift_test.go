@@ -0,0 +1,73 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddrMoved(t *testing.T) {
+	assert.False(t, addrMoved("192.168.1.100", "192.168.1.100"))
+	assert.False(t, addrMoved("192.168.1.100", "192.168.1.100:2377"))
+	assert.False(t, addrMoved("192.168.1.100:2377", "192.168.1.100:2377"))
+	assert.True(t, addrMoved("192.168.1.100", "192.168.1.105"))
+	assert.True(t, addrMoved("192.168.1.100:2377", "192.168.1.100:2380"))
+
+	// Interface names and unset addresses cannot be compared
+	assert.False(t, addrMoved("eth0", "192.168.1.105"))
+	assert.False(t, addrMoved("", "192.168.1.105"))
+	assert.False(t, addrMoved("192.168.1.100", ""))
+}
+
+func TestRefreshListenAddr(t *testing.T) {
+	listen := tfTypes.StringValue("0.0.0.0:2377")
+
+	assert.Equal(t, listen, refreshListenAddr(listen, tfTypes.StringNull(), "192.168.1.100:2377"))
+	assert.Equal(t, "0.0.0.0:2380", refreshListenAddr(listen, tfTypes.StringNull(), "192.168.1.100:2380").ValueString())
+
+	// The raft port is the advertised one when advertise_addr sets a port
+	assert.Equal(t, listen, refreshListenAddr(listen, tfTypes.StringValue("192.168.1.100:2380"), "192.168.1.100:2380"))
+	assert.True(t, refreshListenAddr(tfTypes.StringNull(), tfTypes.StringNull(), "192.168.1.100:2380").IsNull())
+}
+
+func TestSameAddr(t *testing.T) {
+	assert.True(t, sameAddr("10.0.0.1", "10.0.0.1:2377"))
+	assert.True(t, sameAddr("eth0:2377", "eth0"))
+	assert.False(t, sameAddr("10.0.0.1", "10.0.0.2"))
+	assert.False(t, sameAddr("10.0.0.1:2377", "10.0.0.1:2380"))
+	assert.False(t, sameAddr("eth0", "eth1"))
+}
+
+func TestRequiresReplaceIfAddrChanged(t *testing.T) {
+	// Plans the configured address over a resource whose state holds the
+	// given one, as after an import or a previous apply
+	plan := func(state, config tfTypes.String) bool {
+		raw := tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})
+		req := planmodifier.StringRequest{
+			State:       tfsdk.State{Raw: raw},
+			Plan:        tfsdk.Plan{Raw: raw},
+			StateValue:  state,
+			ConfigValue: config,
+			PlanValue:   config,
+		}
+		resp := &planmodifier.StringResponse{PlanValue: config}
+		requiresReplaceIfAddrChanged().PlanModifyString(context.Background(), req, resp)
+		assert.False(t, resp.Diagnostics.HasError())
+		return resp.RequiresReplace
+	}
+
+	// Imported resources keep the configured address
+	assert.False(t, plan(tfTypes.StringNull(), tfTypes.StringValue("10.0.0.1:2377")))
+	assert.False(t, plan(tfTypes.StringNull(), tfTypes.StringValue("eth0")))
+	// The same address written differently, or removed from the configuration
+	assert.False(t, plan(tfTypes.StringValue("10.0.0.1"), tfTypes.StringValue("10.0.0.1:2377")))
+	assert.False(t, plan(tfTypes.StringValue("10.0.0.1"), tfTypes.StringNull()))
+	// Another address, or the live one reported by a refresh after a move
+	assert.True(t, plan(tfTypes.StringValue("10.0.0.1"), tfTypes.StringValue("10.0.0.2")))
+	assert.True(t, plan(tfTypes.StringValue("eth0"), tfTypes.StringValue("eth1")))
+}
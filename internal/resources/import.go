@@ -0,0 +1,38 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sntns/terraform-provider-swarm/internal/docker"
+)
+
+// parseNodeImportID parses an import ID of the form "<host>[,<context>]"
+// into a node connection, e.g. "ssh://root@192.168.1.100,prod". The host may
+// be omitted to use the one of the context, e.g. ",prod".
+func parseNodeImportID(id string) (docker.TfNode, error) {
+	host, context, _ := strings.Cut(id, ",")
+	host = strings.TrimSpace(host)
+	context = strings.TrimSpace(context)
+	if host == "" && context == "" {
+		return docker.TfNode{}, fmt.Errorf("expected import ID of the form \"<host>[,<context>]\" or \",<context>\", got %q", id)
+	}
+
+	node := docker.TfNode{
+		Host:         tfTypes.StringNull(),
+		Context:      tfTypes.StringNull(),
+		SSHOpts:      tfTypes.ListNull(tfTypes.StringType),
+		CertMaterial: tfTypes.StringNull(),
+		KeyMaterial:  tfTypes.StringNull(),
+		CaMaterial:   tfTypes.StringNull(),
+		CertPath:     tfTypes.StringNull(),
+	}
+	if host != "" {
+		node.Host = tfTypes.StringValue(host)
+	}
+	if context != "" {
+		node.Context = tfTypes.StringValue(context)
+	}
+	return node, nil
+}
@@ -0,0 +1,58 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNodeImportID(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		wantHost    string
+		wantContext string
+		wantErr     bool
+	}{
+		{
+			name:     "host only",
+			id:       "ssh://root@192.168.1.100",
+			wantHost: "ssh://root@192.168.1.100",
+		},
+		{
+			name:        "host and context",
+			id:          "tcp://192.168.1.100:2376,prod",
+			wantHost:    "tcp://192.168.1.100:2376",
+			wantContext: "prod",
+		},
+		{
+			name:    "empty",
+			id:      "",
+			wantErr: true,
+		},
+		{
+			name:        "context only",
+			id:          ",prod",
+			wantContext: "prod",
+		},
+		{
+			name:    "separator only",
+			id:      " , ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := parseNodeImportID(tt.id)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHost, node.Host.ValueString())
+			assert.Equal(t, tt.wantContext, node.Context.ValueString())
+			assert.True(t, node.SSHOpts.IsNull())
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// demotionTimeout bounds the wait for a demoted manager to step down before
+// it leaves the swarm.
+const demotionTimeout = 30 * time.Second
+
+// checkManagerRemoval refuses to remove a manager from the raft cluster when
+// the remaining managers would lose the quorum, or when it is the last
+// manager of a swarm that still has other nodes.
+//
+// A demoted manager, or one leaving gracefully, is removed from the raft
+// membership and lowers the quorum; a manager leaving with force stays a
+// member that will never answer again.
+func checkManagerRemoval(ctx context.Context, managerClient *client.Client, nodeID string, demote bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	nodes, err := managerClient.NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		diags.AddError(
+			"Error listing nodes",
+			"Could not list the swarm nodes to check the manager quorum, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+
+	if reason := managerRemovalError(nodes, nodeID, demote); reason != "" {
+		diags.AddError(
+			"Manager Quorum Would Be Lost",
+			reason+" Set allow_quorum_loss to true to proceed anyway.",
+		)
+	}
+	return diags
+}
+
+// managerRemovalError returns why removing the manager from the nodes is
+// unsafe, or an empty string when it is safe.
+func managerRemovalError(nodes []swarm.Node, nodeID string, demote bool) string {
+	managers := 0
+	reachable := 0
+	orphans := 0
+	var target *swarm.Node
+	for i, node := range nodes {
+		if node.ManagerStatus == nil {
+			// Nodes that are down, or that left, have no tasks left to manage
+			if node.Status.State != swarm.NodeStateDown {
+				orphans++
+			}
+			continue
+		}
+		managers++
+		if node.ManagerStatus.Reachability == swarm.ReachabilityReachable {
+			reachable++
+		}
+		if node.ID == nodeID {
+			target = &nodes[i]
+		}
+	}
+	if target == nil {
+		// Not a manager, the raft cluster is not affected
+		return ""
+	}
+
+	if managers == 1 {
+		if orphans == 0 {
+			// No other live node, removing it is removing the swarm
+			return ""
+		}
+		return fmt.Sprintf("Node %s is the last manager of the swarm and %d other node(s) would be left without a manager.",
+			nodeID, orphans)
+	}
+
+	remaining := reachable
+	if target.ManagerStatus.Reachability == swarm.ReachabilityReachable {
+		remaining--
+	}
+	members := managers
+	if demote {
+		members--
+	}
+	quorum := members/2 + 1
+	if remaining < quorum {
+		return fmt.Sprintf("Removing manager %s would leave %d reachable manager(s) while %d are needed for the quorum of %d raft member(s).",
+			nodeID, remaining, quorum, members)
+	}
+	return ""
+}
+
+// waitForDemotion waits until the demoted node no longer reports itself as a
+// manager, so that it can leave the swarm gracefully. The wait is best
+// effort: a node still managing when it ends leaves with force.
+func waitForDemotion(ctx context.Context, nodeClient *client.Client, nodeID string, timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+	for {
+		nodeInfo, err := nodeClient.Info(ctx)
+		if err != nil || !nodeInfo.Swarm.ControlAvailable {
+			return
+		}
+		if time.Now().After(deadline) {
+			tflog.Warn(ctx, "demoted node still reports itself as a manager", map[string]interface{}{
+				"node_id": nodeID,
+			})
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(nodePollInterval):
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+)
+
+func testManager(id string, reachability swarm.Reachability) swarm.Node {
+	return swarm.Node{
+		ID:            id,
+		Spec:          swarm.NodeSpec{Role: swarm.NodeRoleManager},
+		ManagerStatus: &swarm.ManagerStatus{Reachability: reachability},
+	}
+}
+
+func testWorker(id string) swarm.Node {
+	return swarm.Node{ID: id, Spec: swarm.NodeSpec{Role: swarm.NodeRoleWorker}}
+}
+
+func TestManagerRemovalError(t *testing.T) {
+	healthy := []swarm.Node{
+		testManager("m1", swarm.ReachabilityReachable),
+		testManager("m2", swarm.ReachabilityReachable),
+		testManager("m3", swarm.ReachabilityReachable),
+		testWorker("w1"),
+	}
+	degraded := []swarm.Node{
+		testManager("m1", swarm.ReachabilityReachable),
+		testManager("m2", swarm.ReachabilityReachable),
+		testManager("m3", swarm.ReachabilityUnreachable),
+	}
+
+	// One manager out of three healthy ones can go, with or without force
+	assert.Empty(t, managerRemovalError(healthy, "m1", false))
+	assert.Empty(t, managerRemovalError(healthy, "m1", true))
+
+	// Workers do not take part in the quorum
+	assert.Empty(t, managerRemovalError(healthy, "w1", false))
+
+	// With one manager down, a forced leave keeps three raft members and only one reachable
+	assert.NotEmpty(t, managerRemovalError(degraded, "m1", false))
+	// Demoting shrinks the membership to two, which needs both remaining managers
+	assert.NotEmpty(t, managerRemovalError(degraded, "m1", true))
+	// The unreachable manager can be demoted safely
+	assert.Empty(t, managerRemovalError(degraded, "m3", true))
+
+	// The last manager of a swarm with workers
+	assert.NotEmpty(t, managerRemovalError([]swarm.Node{testManager("m1", swarm.ReachabilityReachable), testWorker("w1")}, "m1", false))
+	// Workers that are down are not left behind
+	down := testWorker("w1")
+	down.Status.State = swarm.NodeStateDown
+	assert.Empty(t, managerRemovalError([]swarm.Node{testManager("m1", swarm.ReachabilityReachable), down}, "m1", false))
+	// A single node swarm can be removed
+	assert.Empty(t, managerRemovalError([]swarm.Node{testManager("m1", swarm.ReachabilityReachable)}, "m1", false))
+}
@@ -0,0 +1,97 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultReadyTimeout bounds the wait for a joined node to be ready.
+const defaultReadyTimeout = "2m"
+
+// waitForNodeReady waits until the managers report the node as ready and,
+// for a manager, reachable in the raft cluster. Without a manager
+// connection, it waits until the node itself reports an active swarm state.
+func waitForNodeReady(ctx context.Context, nodeClient, managerClient *client.Client, nodeID string, role swarm.NodeRole, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var ready bool
+		var status string
+		if managerClient != nil {
+			node, _, err := managerClient.NodeInspectWithRaw(ctx, nodeID)
+			if err != nil && !client.IsErrNotFound(err) {
+				diags.AddError(
+					"Error inspecting node",
+					"Could not inspect node "+nodeID+" while waiting for it to be ready, unexpected error: "+err.Error(),
+				)
+				return diags
+			}
+			if err == nil {
+				ready, status = nodeReady(node, role)
+			} else {
+				status = "not known by the managers yet"
+			}
+		} else {
+			nodeInfo, err := nodeClient.Info(ctx)
+			if err != nil {
+				diags.AddError(
+					"Error getting node info",
+					"Could not get node info while waiting for it to be ready, unexpected error: "+err.Error(),
+				)
+				return diags
+			}
+			ready = nodeInfo.Swarm.LocalNodeState == swarm.LocalNodeStateActive
+			status = "in swarm state " + string(nodeInfo.Swarm.LocalNodeState)
+		}
+		if ready {
+			return diags
+		}
+
+		if time.Now().After(deadline) {
+			diags.AddError(
+				"Timeout Waiting for Node",
+				fmt.Sprintf("Node %s is still %s after %s.", nodeID, status, timeout),
+			)
+			return diags
+		}
+		tflog.Debug(ctx, "waiting for node to be ready", map[string]interface{}{
+			"node_id": nodeID,
+			"status":  status,
+		})
+
+		select {
+		case <-ctx.Done():
+			diags.AddError(
+				"Waiting for Node Interrupted",
+				"Stopped waiting for node "+nodeID+" to be ready: "+ctx.Err().Error(),
+			)
+			return diags
+		case <-time.After(nodePollInterval):
+		}
+	}
+}
+
+// nodeReady reports whether the node is ready for its role, with a
+// description of its status otherwise.
+func nodeReady(node swarm.Node, role swarm.NodeRole) (bool, string) {
+	if node.Status.State != swarm.NodeStateReady {
+		return false, string(node.Status.State)
+	}
+	if role != swarm.NodeRoleManager {
+		return true, ""
+	}
+	if node.ManagerStatus == nil {
+		return false, "not a manager yet"
+	}
+	if node.ManagerStatus.Reachability != swarm.ReachabilityReachable {
+		return false, string(node.ManagerStatus.Reachability) + " in the raft cluster"
+	}
+	return true, ""
+}
@@ -0,0 +1,32 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeReady(t *testing.T) {
+	node := swarm.Node{Status: swarm.NodeStatus{State: swarm.NodeStateUnknown}}
+	ready, status := nodeReady(node, swarm.NodeRoleWorker)
+	assert.False(t, ready)
+	assert.Equal(t, "unknown", status)
+
+	node.Status.State = swarm.NodeStateReady
+	ready, _ = nodeReady(node, swarm.NodeRoleWorker)
+	assert.True(t, ready)
+
+	// A manager must also be reachable in the raft cluster
+	ready, status = nodeReady(node, swarm.NodeRoleManager)
+	assert.False(t, ready)
+	assert.Equal(t, "not a manager yet", status)
+
+	node.ManagerStatus = &swarm.ManagerStatus{Reachability: swarm.ReachabilityUnknown}
+	ready, _ = nodeReady(node, swarm.NodeRoleManager)
+	assert.False(t, ready)
+
+	node.ManagerStatus.Reachability = swarm.ReachabilityReachable
+	ready, _ = nodeReady(node, swarm.NodeRoleManager)
+	assert.True(t, ready)
+}
@@ -0,0 +1,1065 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sntns/terraform-provider-swarm/internal/docker"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &swarmClusterResource{}
+	_ resource.ResourceWithConfigure      = &swarmClusterResource{}
+	_ resource.ResourceWithValidateConfig = &swarmClusterResource{}
+	_ resource.ResourceWithModifyPlan     = &swarmClusterResource{}
+)
+
+// NewSwarmClusterResource is a helper function to simplify the provider implementation.
+func NewSwarmClusterResource() resource.Resource {
+	return &swarmClusterResource{}
+}
+
+// swarmClusterResource is the resource implementation.
+type swarmClusterResource struct {
+	providerData *SwarmProviderData
+}
+
+// swarmClusterResourceModel maps the resource schema data.
+type swarmClusterResourceModel struct {
+	ID           tfTypes.String            `tfsdk:"id"`
+	Managers     []swarmClusterMemberModel `tfsdk:"managers"`
+	Workers      []swarmClusterMemberModel `tfsdk:"workers"`
+	NodeIDs      tfTypes.Map               `tfsdk:"node_ids"`
+	ManagerToken tfTypes.String            `tfsdk:"manager_token"`
+	WorkerToken  tfTypes.String            `tfsdk:"worker_token"`
+
+	NodeTimeout     tfTypes.String `tfsdk:"node_timeout"`
+	AllowQuorumLoss tfTypes.Bool   `tfsdk:"allow_quorum_loss"`
+}
+
+// swarmClusterMemberModel maps a node of the cluster: its Docker connection,
+// as in docker.TfNode, and its swarm settings.
+type swarmClusterMemberModel struct {
+	Host         tfTypes.String `tfsdk:"host"`
+	Context      tfTypes.String `tfsdk:"context"`
+	SSHOpts      tfTypes.List   `tfsdk:"ssh_opts"`
+	CertMaterial tfTypes.String `tfsdk:"cert_material"`
+	KeyMaterial  tfTypes.String `tfsdk:"key_material"`
+	CaMaterial   tfTypes.String `tfsdk:"ca_material"`
+	CertPath     tfTypes.String `tfsdk:"cert_path"`
+	SSH          *docker.TfSSH  `tfsdk:"ssh"`
+
+	AdvertiseAddr tfTypes.String `tfsdk:"advertise_addr"`
+	ListenAddr    tfTypes.String `tfsdk:"listen_addr"`
+	Labels        tfTypes.Map    `tfsdk:"labels"`
+}
+
+// node returns the Docker connection of the member.
+func (m swarmClusterMemberModel) node() docker.TfNode {
+	return docker.TfNode{
+		Host:         m.Host,
+		Context:      m.Context,
+		SSHOpts:      m.SSHOpts,
+		CertMaterial: m.CertMaterial,
+		KeyMaterial:  m.KeyMaterial,
+		CaMaterial:   m.CaMaterial,
+		CertPath:     m.CertPath,
+		SSH:          m.SSH,
+	}
+}
+
+// key identifies the member in node_ids: its Docker host, followed by its
+// Docker context when set.
+func (m swarmClusterMemberModel) key() string {
+	return m.node().ID()
+}
+
+// client returns a Docker client connected to the member.
+func (m swarmClusterMemberModel) client(providerData *SwarmProviderData) (*client.Client, error) {
+	node := m.node()
+	return providerData.NewClient(&node)
+}
+
+const (
+	// clusterJoinAttempts is the number of rounds over the managers when a
+	// member joins the cluster.
+	clusterJoinAttempts = defaultJoinAttempts
+	// clusterJoinAttemptTimeout bounds a single join request of a member.
+	clusterJoinAttemptTimeout = time.Minute
+	// clusterJoinBackoff is the delay before the second round.
+	clusterJoinBackoff = 2 * time.Second
+)
+
+// Metadata returns the resource type name.
+func (r *swarmClusterResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cluster"
+}
+
+// clusterMemberSchema describes a node of the cluster: the attributes of
+// docker.NodeSchema and the swarm settings of the node.
+func clusterMemberSchema() schema.NestedAttributeObject {
+	attributes := map[string]schema.Attribute{
+		"advertise_addr": schema.StringAttribute{
+			Description: "Externally reachable address advertised to other nodes",
+			Optional:    true,
+		},
+		"listen_addr": schema.StringAttribute{
+			Description: "Listen address for the raft consensus protocol (managers only)",
+			Optional:    true,
+		},
+		"labels": schema.MapAttribute{
+			Description: "Labels of the node, usable in placement constraints as node.labels.<key>",
+			Optional:    true,
+			ElementType: tfTypes.StringType,
+		},
+	}
+	for name, attribute := range docker.NodeSchema.Attributes {
+		attributes[name] = attribute
+	}
+	return schema.NestedAttributeObject{Attributes: attributes}
+}
+
+// Schema defines the schema for the resource.
+func (r *swarmClusterResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Build a whole Docker Swarm cluster from its manager and worker nodes.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "ID of the swarm cluster",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"managers": schema.ListNestedAttribute{
+				Description:  "Manager nodes of the cluster, the first one initializing the swarm",
+				Required:     true,
+				NestedObject: clusterMemberSchema(),
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"workers": schema.ListNestedAttribute{
+				Description:  "Worker nodes of the cluster",
+				Optional:     true,
+				NestedObject: clusterMemberSchema(),
+			},
+			"node_ids": schema.MapAttribute{
+				Description: "Swarm node IDs of the members, by Docker host",
+				Computed:    true,
+				ElementType: tfTypes.StringType,
+			},
+			"manager_token": schema.StringAttribute{
+				Description: "Token for joining the cluster as a manager",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"worker_token": schema.StringAttribute{
+				Description: "Token for joining the cluster as a worker",
+				Computed:    true,
+				Sensitive:   true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"node_timeout": schema.StringAttribute{
+				Description: "Maximum time to wait for a node to be ready after joining, or down before removing it",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultReadyTimeout),
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"allow_quorum_loss": schema.BoolAttribute{
+				Description: "Demote or remove managers even when the remaining managers would lose the raft quorum",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// Configure keeps the provider data, whose API version applies to the nodes.
+func (r *swarmClusterResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, diags := ConfiguredProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	r.providerData = providerData
+}
+
+// ValidateConfig checks that every node appears only once in the cluster.
+func (r *swarmClusterResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config swarmClusterResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	seen := map[string]bool{}
+	check := func(attribute string, members []swarmClusterMemberModel) {
+		for i, member := range members {
+			if member.Host.IsUnknown() || member.Context.IsUnknown() {
+				continue
+			}
+			if member.key() == "" {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(attribute).AtListIndex(i).AtName("host"),
+					"Missing Node Connection",
+					"Each node of the cluster needs a host or a context.",
+				)
+				continue
+			}
+			if seen[member.key()] {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(attribute).AtListIndex(i).AtName("host"),
+					"Duplicate Cluster Node",
+					"Node "+member.key()+" is listed more than once in the cluster.",
+				)
+			}
+			seen[member.key()] = true
+		}
+	}
+	check("managers", config.Managers)
+	check("workers", config.Workers)
+}
+
+// ModifyPlan refuses address changes of the nodes already part of the
+// cluster, which only take effect when a node joins.
+func (r *swarmClusterResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state swarmClusterResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(checkMemberAddrs(&state, &plan)...)
+}
+
+func (r *swarmClusterResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan swarmClusterResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nodeTimeout, err := time.ParseDuration(plan.NodeTimeout.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid node_timeout",
+			"Could not parse node_timeout "+plan.NodeTimeout.ValueString()+" as a duration: "+err.Error(),
+		)
+		return
+	}
+
+	bootstrap := plan.Managers[0]
+	managerClient, err := bootstrap.client(r.providerData)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client",
+			"An unexpected error occurred when creating the Docker client of "+bootstrap.key()+". \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	nodeIDs := map[string]string{}
+	nodeID, diags := r.bootstrap(ctx, managerClient, bootstrap)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	nodeIDs[bootstrap.key()] = nodeID
+
+	resp.Diagnostics.Append(r.addMembers(ctx, managerClient, plan.Managers[1:], plan.Workers, nodeIDs, nodeTimeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, member := range append(append([]swarmClusterMemberModel{}, plan.Managers...), plan.Workers...) {
+		resp.Diagnostics.Append(applyMemberLabels(ctx, managerClient, nodeIDs[member.key()], tfTypes.MapNull(tfTypes.StringType), member.Labels)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(r.refresh(ctx, managerClient, &plan, nodeIDs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the membership and the roles of the nodes of the cluster.
+func (r *swarmClusterResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state swarmClusterResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	members := append(append([]swarmClusterMemberModel{}, state.Managers...), state.Workers...)
+	managerClient, inactive, err := connectManager(ctx, r.providerData, members)
+	if inactive {
+		tflog.Debug(ctx, "swarm cluster is gone", map[string]interface{}{
+			"cluster_id": state.ID.ValueString(),
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Reach a Manager",
+			"Could not connect to a manager of cluster "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	swarmInfo, err := managerClient.SwarmInspect(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading swarm",
+			"Could not inspect swarm, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	if swarmInfo.ID != state.ID.ValueString() {
+		tflog.Debug(ctx, "swarm cluster was replaced", map[string]interface{}{
+			"cluster_id": state.ID.ValueString(),
+			"actual":     swarmInfo.ID,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	nodes, err := managerClient.NodeList(ctx, types.NodeListOptions{})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error listing nodes",
+			"Could not list the nodes of the swarm, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	nodeIDs := map[string]string{}
+	diags = state.NodeIDs.ElementsAs(ctx, &nodeIDs, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(flattenClusterMembers(ctx, nodes, &state, nodeIDs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.refresh(ctx, managerClient, &state, nodeIDs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update reconciles the cluster: new nodes join first, then roles and
+// labels are changed, and the nodes no longer listed leave last so that the
+// bootstrap manager can be replaced.
+func (r *swarmClusterResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state swarmClusterResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nodeTimeout, err := time.ParseDuration(plan.NodeTimeout.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid node_timeout",
+			"Could not parse node_timeout "+plan.NodeTimeout.ValueString()+" as a duration: "+err.Error(),
+		)
+		return
+	}
+
+	nodeIDs := map[string]string{}
+	diags = state.NodeIDs.ElementsAs(ctx, &nodeIDs, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	changes := planClusterChanges(&state, &plan)
+
+	// The managers staying in the cluster are preferred to run the changes
+	managerClient, _, err := connectManager(ctx, r.providerData, append(changes.staying, state.Managers...))
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Reach a Manager",
+			"Could not connect to a manager of cluster "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(r.addMembers(ctx, managerClient, changes.joinManagers, changes.joinWorkers, nodeIDs, nodeTimeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, member := range changes.promote {
+		nodeID := nodeIDs[member.key()]
+		resp.Diagnostics.Append(setNodeRole(ctx, managerClient, nodeID, swarm.NodeRoleManager)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Managers are promoted one at a time to keep the raft cluster stable
+		resp.Diagnostics.Append(waitForNodeReady(ctx, nil, managerClient, nodeID, swarm.NodeRoleManager, nodeTimeout)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Every planned manager is now a manager, one of them runs the demotions
+	// and removals since the current one may be demoted or removed
+	managerClient, _, err = connectManager(ctx, r.providerData, plan.Managers)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Reach a Manager",
+			"Could not connect to a planned manager of cluster "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	for _, member := range changes.demote {
+		nodeID := nodeIDs[member.key()]
+		if !plan.AllowQuorumLoss.ValueBool() {
+			resp.Diagnostics.Append(checkManagerRemoval(ctx, managerClient, nodeID, true)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+		resp.Diagnostics.Append(setNodeRole(ctx, managerClient, nodeID, swarm.NodeRoleWorker)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	priorLabels := map[string]tfTypes.Map{}
+	for _, member := range append(append([]swarmClusterMemberModel{}, state.Managers...), state.Workers...) {
+		priorLabels[member.key()] = member.Labels
+	}
+	for _, member := range append(append([]swarmClusterMemberModel{}, plan.Managers...), plan.Workers...) {
+		prior, ok := priorLabels[member.key()]
+		if !ok {
+			prior = tfTypes.MapNull(tfTypes.StringType)
+		}
+		if prior.Equal(member.Labels) {
+			continue
+		}
+		resp.Diagnostics.Append(applyMemberLabels(ctx, managerClient, nodeIDs[member.key()], prior, member.Labels)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	for _, removed := range changes.remove {
+		resp.Diagnostics.Append(r.removeMember(ctx, managerClient, removed, nodeIDs[removed.member.key()], nodeTimeout, plan.AllowQuorumLoss.ValueBool())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		delete(nodeIDs, removed.member.key())
+	}
+
+	plan.ID = state.ID
+	resp.Diagnostics.Append(r.refresh(ctx, managerClient, &plan, nodeIDs)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete makes every node of the cluster leave the swarm, workers first.
+func (r *swarmClusterResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state swarmClusterResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	members := append([]swarmClusterMemberModel{}, state.Workers...)
+	for i := len(state.Managers) - 1; i >= 0; i-- {
+		members = append(members, state.Managers[i])
+	}
+	for _, member := range members {
+		resp.Diagnostics.Append(leaveMember(ctx, r.providerData, member)...)
+	}
+}
+
+// bootstrap initializes the swarm on the first manager, or keeps the swarm it
+// already manages, and returns its node ID.
+func (r *swarmClusterResource) bootstrap(ctx context.Context, managerClient *client.Client, member swarmClusterMemberModel) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	nodeInfo, err := managerClient.Info(ctx)
+	if err != nil {
+		diags.AddError(
+			"Error getting node info",
+			"Could not get the node info of "+member.key()+", unexpected error: "+err.Error(),
+		)
+		return "", diags
+	}
+	switch {
+	case nodeInfo.Swarm.LocalNodeState == swarm.LocalNodeStateInactive:
+		initRequest := swarm.InitRequest{
+			AdvertiseAddr: member.AdvertiseAddr.ValueString(),
+			ListenAddr:    member.ListenAddr.ValueString(),
+		}
+		nodeID, err := managerClient.SwarmInit(ctx, initRequest)
+		if err != nil {
+			diags.AddError(
+				"Error initializing swarm",
+				"Could not initialize swarm on "+member.key()+", unexpected error: "+err.Error(),
+			)
+			return "", diags
+		}
+		tflog.Trace(ctx, "initialized swarm", map[string]interface{}{
+			"node_id": nodeID,
+		})
+		return nodeID, diags
+	case nodeInfo.Swarm.ControlAvailable:
+		// A previous apply failed after the swarm was initialized
+		tflog.Debug(ctx, "bootstrap node already manages a swarm", map[string]interface{}{
+			"node_id": nodeInfo.Swarm.NodeID,
+		})
+		return nodeInfo.Swarm.NodeID, diags
+	default:
+		diags.AddError(
+			"Node Already Part of a Swarm",
+			"The first manager "+member.key()+" is already a worker of a swarm and cannot initialize the cluster.",
+		)
+		return "", diags
+	}
+}
+
+// addMembers joins the managers one at a time, each one being reachable
+// before the next joins, then the workers in parallel.
+func (r *swarmClusterResource) addMembers(ctx context.Context, managerClient *client.Client, managers, workers []swarmClusterMemberModel, nodeIDs map[string]string, timeout time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if len(managers) == 0 && len(workers) == 0 {
+		return diags
+	}
+	swarmInfo, err := managerClient.SwarmInspect(ctx)
+	if err != nil {
+		diags.AddError(
+			"Error inspecting swarm",
+			"Could not inspect swarm to read the join tokens, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+
+	for _, member := range managers {
+		remoteAddrs, addrDiags := listManagerAddrs(ctx, managerClient)
+		diags.Append(addrDiags...)
+		if diags.HasError() {
+			return diags
+		}
+		joinRequest := swarm.JoinRequest{JoinToken: swarmInfo.JoinTokens.Manager, RemoteAddrs: remoteAddrs}
+		nodeID, joinDiags := joinMember(ctx, r.providerData, managerClient, member, joinRequest, swarm.NodeRoleManager, timeout)
+		diags.Append(joinDiags...)
+		if diags.HasError() {
+			return diags
+		}
+		nodeIDs[member.key()] = nodeID
+	}
+
+	if len(workers) == 0 {
+		return diags
+	}
+	remoteAddrs, addrDiags := listManagerAddrs(ctx, managerClient)
+	diags.Append(addrDiags...)
+	if diags.HasError() {
+		return diags
+	}
+	joinRequest := swarm.JoinRequest{JoinToken: swarmInfo.JoinTokens.Worker, RemoteAddrs: remoteAddrs}
+
+	workerIDs := make([]string, len(workers))
+	workerDiags := make([]diag.Diagnostics, len(workers))
+	var wg sync.WaitGroup
+	for i, member := range workers {
+		wg.Add(1)
+		go func(i int, member swarmClusterMemberModel) {
+			defer wg.Done()
+			workerIDs[i], workerDiags[i] = joinMember(ctx, r.providerData, managerClient, member, joinRequest, swarm.NodeRoleWorker, timeout)
+		}(i, member)
+	}
+	wg.Wait()
+
+	for i, member := range workers {
+		diags.Append(workerDiags[i]...)
+		if !workerDiags[i].HasError() {
+			nodeIDs[member.key()] = workerIDs[i]
+		}
+	}
+	return diags
+}
+
+// joinMember joins the member to the swarm unless it is already part of it,
+// and waits until it is ready for its role.
+func joinMember(ctx context.Context, providerData *SwarmProviderData, managerClient *client.Client, member swarmClusterMemberModel, joinRequest swarm.JoinRequest, role swarm.NodeRole, timeout time.Duration) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	nodeClient, err := member.client(providerData)
+	if err != nil {
+		diags.AddError(
+			"Unable to Create Docker Client",
+			"An unexpected error occurred when creating the Docker client of "+member.key()+". \n\nDocker Client Error: "+err.Error(),
+		)
+		return "", diags
+	}
+
+	nodeInfo, err := nodeClient.Info(ctx)
+	if err != nil {
+		diags.AddError(
+			"Error getting node info",
+			"Could not get the node info of "+member.key()+", unexpected error: "+err.Error(),
+		)
+		return "", diags
+	}
+	if nodeInfo.Swarm.LocalNodeState != swarm.LocalNodeStateInactive {
+		// A node known by the managers is already part of the cluster
+		_, _, err := managerClient.NodeInspectWithRaw(ctx, nodeInfo.Swarm.NodeID)
+		if err != nil {
+			diags.AddError(
+				"Node Already Part of a Swarm",
+				"Node "+member.key()+" is already part of another swarm. Make it leave that swarm before adding it to the cluster.",
+			)
+			return "", diags
+		}
+		diags.Append(setNodeRole(ctx, managerClient, nodeInfo.Swarm.NodeID, role)...)
+		if diags.HasError() {
+			return "", diags
+		}
+	} else {
+		joinRequest.AdvertiseAddr = member.AdvertiseAddr.ValueString()
+		joinRequest.ListenAddr = member.ListenAddr.ValueString()
+		diags.Append(joinSwarm(ctx, nodeClient, joinRequest, clusterJoinAttempts, clusterJoinAttemptTimeout, clusterJoinBackoff)...)
+		if diags.HasError() {
+			return "", diags
+		}
+		nodeInfo, err = nodeClient.Info(ctx)
+		if err != nil {
+			diags.AddError(
+				"Error getting node info",
+				"Could not get the node info of "+member.key()+" after joining swarm, unexpected error: "+err.Error(),
+			)
+			return "", diags
+		}
+	}
+
+	diags.Append(waitForNodeReady(ctx, nodeClient, managerClient, nodeInfo.Swarm.NodeID, role, timeout)...)
+	if diags.HasError() {
+		return "", diags
+	}
+	tflog.Trace(ctx, "added cluster node", map[string]interface{}{
+		"host":    member.key(),
+		"node_id": nodeInfo.Swarm.NodeID,
+		"role":    string(role),
+	})
+	return nodeInfo.Swarm.NodeID, diags
+}
+
+// removeMember makes the node leave the swarm, after demoting it when it is
+// a manager, and removes it from the node list.
+func (r *swarmClusterResource) removeMember(ctx context.Context, managerClient *client.Client, removed clusterMember, nodeID string, timeout time.Duration, allowQuorumLoss bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if nodeID == "" {
+		return diags
+	}
+	if removed.role == swarm.NodeRoleManager {
+		if !allowQuorumLoss {
+			diags.Append(checkManagerRemoval(ctx, managerClient, nodeID, true)...)
+			if diags.HasError() {
+				return diags
+			}
+		}
+		diags.Append(setNodeRole(ctx, managerClient, nodeID, swarm.NodeRoleWorker)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	// A node that cannot be reached is removed once the managers see it down
+	for _, d := range leaveMember(ctx, r.providerData, removed.member) {
+		tflog.Debug(ctx, "node could not leave the swarm", map[string]interface{}{
+			"host":  removed.member.key(),
+			"error": d.Detail(),
+		})
+	}
+
+	diags.Append(removeNode(ctx, managerClient, nodeID, timeout)...)
+	return diags
+}
+
+// leaveMember makes the node leave the swarm it is part of.
+func leaveMember(ctx context.Context, providerData *SwarmProviderData, member swarmClusterMemberModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	nodeClient, err := member.client(providerData)
+	if err != nil {
+		diags.AddError(
+			"Unable to Create Docker Client in Delete",
+			"An unexpected error occurred when creating the Docker client of "+member.key()+". \n\nDocker Client Error: "+err.Error(),
+		)
+		return diags
+	}
+	nodeInfo, err := nodeClient.Info(ctx)
+	if err != nil {
+		diags.AddError(
+			"Error getting node info",
+			"Could not get the node info of "+member.key()+" before leaving swarm, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	if nodeInfo.Swarm.LocalNodeState == swarm.LocalNodeStateInactive {
+		return diags
+	}
+
+	err = nodeClient.SwarmLeave(ctx, true)
+	if err != nil {
+		diags.AddError(
+			"Error Leaving Swarm",
+			"Could not make "+member.key()+" leave the swarm, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	tflog.Trace(ctx, "left swarm", map[string]interface{}{
+		"host":    member.key(),
+		"node_id": nodeInfo.Swarm.NodeID,
+	})
+	return diags
+}
+
+// refresh reads the cluster ID and the join tokens, and sets node_ids.
+func (r *swarmClusterResource) refresh(ctx context.Context, managerClient *client.Client, model *swarmClusterResourceModel, nodeIDs map[string]string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	swarmInfo, err := managerClient.SwarmInspect(ctx)
+	if err != nil {
+		diags.AddError(
+			"Error inspecting swarm",
+			"Could not inspect swarm, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	model.ID = tfTypes.StringValue(swarmInfo.ID)
+	model.ManagerToken = tfTypes.StringValue(swarmInfo.JoinTokens.Manager)
+	model.WorkerToken = tfTypes.StringValue(swarmInfo.JoinTokens.Worker)
+	model.NodeIDs, diags = tfTypes.MapValueFrom(ctx, tfTypes.StringType, nodeIDs)
+	return diags
+}
+
+// connectManager returns a client connected to the first member that is a
+// manager of a swarm. inactive reports that every member answered and none
+// is part of a swarm anymore.
+func connectManager(ctx context.Context, providerData *SwarmProviderData, members []swarmClusterMemberModel) (*client.Client, bool, error) {
+	inactive := len(members) > 0
+	var lastErr error
+	for _, member := range members {
+		nodeClient, err := member.client(providerData)
+		if err != nil {
+			inactive = false
+			lastErr = err
+			continue
+		}
+		nodeInfo, err := nodeClient.Info(ctx)
+		if err != nil {
+			inactive = false
+			lastErr = fmt.Errorf("%s: %w", member.key(), err)
+			continue
+		}
+		if nodeInfo.Swarm.ControlAvailable {
+			return nodeClient, false, nil
+		}
+		if nodeInfo.Swarm.LocalNodeState != swarm.LocalNodeStateInactive {
+			inactive = false
+		}
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("none of the %d node(s) is a manager of the swarm", len(members))
+	}
+	return nil, inactive, lastErr
+}
+
+// listManagerAddrs returns the raft addresses of the reachable managers.
+func listManagerAddrs(ctx context.Context, managerClient *client.Client) ([]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	args := filters.NewArgs(filters.Arg("role", string(swarm.NodeRoleManager)))
+	nodes, err := managerClient.NodeList(ctx, types.NodeListOptions{Filters: args})
+	if err != nil {
+		diags.AddError(
+			"Error listing nodes",
+			"Could not list the managers of the swarm, unexpected error: "+err.Error(),
+		)
+		return nil, diags
+	}
+	return managerAddrs(nodes), diags
+}
+
+// applyMemberLabels sets the configured labels of the node and removes the
+// ones previously configured, leaving the other labels untouched.
+func applyMemberLabels(ctx context.Context, managerClient *client.Client, nodeID string, prior, desired tfTypes.Map) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if nodeID == "" || (prior.IsNull() && desired.IsNull()) {
+		return diags
+	}
+	priorLabels := map[string]string{}
+	diags.Append(prior.ElementsAs(ctx, &priorLabels, false)...)
+	desiredLabels := map[string]string{}
+	diags.Append(desired.ElementsAs(ctx, &desiredLabels, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	node, _, err := managerClient.NodeInspectWithRaw(ctx, nodeID)
+	if err != nil {
+		diags.AddError(
+			"Error inspecting node",
+			"Could not inspect node "+nodeID+" before updating its labels, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	labels, changed := mergeLabels(node.Spec.Labels, priorLabels, desiredLabels)
+	if !changed {
+		return diags
+	}
+
+	spec := node.Spec
+	spec.Labels = labels
+	err = managerClient.NodeUpdate(ctx, nodeID, node.Version, spec)
+	if err != nil {
+		diags.AddError(
+			"Error updating node labels",
+			"Could not update the labels of node "+nodeID+", unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	tflog.Trace(ctx, "updated node labels", map[string]interface{}{
+		"node_id": nodeID,
+	})
+	return diags
+}
+
+// mergeLabels returns the current labels without the prior ones and with the
+// desired ones, and whether they changed.
+func mergeLabels(current, prior, desired map[string]string) (map[string]string, bool) {
+	labels := map[string]string{}
+	for key, value := range current {
+		labels[key] = value
+	}
+	changed := false
+	for key := range prior {
+		if _, ok := desired[key]; ok {
+			continue
+		}
+		if _, ok := labels[key]; ok {
+			delete(labels, key)
+			changed = true
+		}
+	}
+	for key, value := range desired {
+		if current, ok := labels[key]; !ok || current != value {
+			labels[key] = value
+			changed = true
+		}
+	}
+	return labels, changed
+}
+
+// clusterMember is a member of the cluster with its role.
+type clusterMember struct {
+	member swarmClusterMemberModel
+	role   swarm.NodeRole
+}
+
+// clusterChanges lists the operations reconciling the cluster with the plan.
+type clusterChanges struct {
+	// staying are the planned managers that were already managers
+	staying      []swarmClusterMemberModel
+	joinManagers []swarmClusterMemberModel
+	joinWorkers  []swarmClusterMemberModel
+	promote      []swarmClusterMemberModel
+	demote       []swarmClusterMemberModel
+	// remove lists the workers first, then the managers
+	remove []clusterMember
+}
+
+// planClusterChanges compares the members of the state and of the plan.
+func planClusterChanges(state, plan *swarmClusterResourceModel) clusterChanges {
+	var changes clusterChanges
+
+	prior := map[string]swarm.NodeRole{}
+	for _, member := range state.Managers {
+		prior[member.key()] = swarm.NodeRoleManager
+	}
+	for _, member := range state.Workers {
+		prior[member.key()] = swarm.NodeRoleWorker
+	}
+	planned := map[string]bool{}
+
+	for _, member := range plan.Managers {
+		planned[member.key()] = true
+		switch prior[member.key()] {
+		case swarm.NodeRoleManager:
+			changes.staying = append(changes.staying, member)
+		case swarm.NodeRoleWorker:
+			changes.promote = append(changes.promote, member)
+		default:
+			changes.joinManagers = append(changes.joinManagers, member)
+		}
+	}
+	for _, member := range plan.Workers {
+		planned[member.key()] = true
+		switch prior[member.key()] {
+		case swarm.NodeRoleManager:
+			changes.demote = append(changes.demote, member)
+		case swarm.NodeRoleWorker:
+		default:
+			changes.joinWorkers = append(changes.joinWorkers, member)
+		}
+	}
+
+	for _, member := range state.Workers {
+		if !planned[member.key()] {
+			changes.remove = append(changes.remove, clusterMember{member: member, role: swarm.NodeRoleWorker})
+		}
+	}
+	for _, member := range state.Managers {
+		if !planned[member.key()] {
+			changes.remove = append(changes.remove, clusterMember{member: member, role: swarm.NodeRoleManager})
+		}
+	}
+	return changes
+}
+
+// checkMemberAddrs reports the members of the plan whose configured
+// advertise_addr or listen_addr differs from the one they joined with.
+// Removing an address from the configuration is not a change.
+func checkMemberAddrs(state, plan *swarmClusterResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	prior := map[string]swarmClusterMemberModel{}
+	for _, member := range append(append([]swarmClusterMemberModel{}, state.Managers...), state.Workers...) {
+		prior[member.key()] = member
+	}
+	checkAddr := func(attrPath path.Path, key, name string, joined, planned tfTypes.String) {
+		if !isKnown(planned) || joined.Equal(planned) {
+			return
+		}
+		diags.AddAttributeError(
+			attrPath.AtName(name),
+			"Cluster Node Address Change Not Supported",
+			"Node "+key+" is already part of the cluster and its "+name+" cannot be changed in place. "+
+				"Remove the node from the cluster, then add it back with the new address in a later apply.",
+		)
+	}
+	check := func(attribute string, members []swarmClusterMemberModel) {
+		for i, member := range members {
+			joined, ok := prior[member.key()]
+			if !ok {
+				continue
+			}
+			attrPath := path.Root(attribute).AtListIndex(i)
+			checkAddr(attrPath, member.key(), "advertise_addr", joined.AdvertiseAddr, member.AdvertiseAddr)
+			checkAddr(attrPath, member.key(), "listen_addr", joined.ListenAddr, member.ListenAddr)
+		}
+	}
+	check("managers", plan.Managers)
+	check("workers", plan.Workers)
+	return diags
+}
+
+// flattenClusterMembers drops the members that are not part of the swarm
+// anymore, moves the members to the list of their actual role, and refreshes
+// the values of their configured labels.
+func flattenClusterMembers(ctx context.Context, nodes []swarm.Node, model *swarmClusterResourceModel, nodeIDs map[string]string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	byID := map[string]swarm.Node{}
+	for _, node := range nodes {
+		byID[node.ID] = node
+	}
+
+	var managers, workers []swarmClusterMemberModel
+	for _, member := range append(append([]swarmClusterMemberModel{}, model.Managers...), model.Workers...) {
+		node, ok := byID[nodeIDs[member.key()]]
+		if !ok {
+			delete(nodeIDs, member.key())
+			continue
+		}
+
+		if !member.Labels.IsNull() {
+			labels := map[string]string{}
+			for key := range member.Labels.Elements() {
+				if value, ok := node.Spec.Labels[key]; ok {
+					labels[key] = value
+				}
+			}
+			var labelDiags diag.Diagnostics
+			member.Labels, labelDiags = tfTypes.MapValueFrom(ctx, tfTypes.StringType, labels)
+			diags.Append(labelDiags...)
+		}
+
+		if node.Spec.Role == swarm.NodeRoleManager {
+			managers = append(managers, member)
+		} else {
+			workers = append(workers, member)
+		}
+	}
+	// Keep an empty list of workers distinct from an omitted one
+	if workers == nil && model.Workers != nil {
+		workers = []swarmClusterMemberModel{}
+	}
+	model.Managers = managers
+	model.Workers = workers
+	return diags
+}
@@ -0,0 +1,190 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwarmClusterResource_Metadata(t *testing.T) {
+	r := NewSwarmClusterResource()
+
+	req := resource.MetadataRequest{
+		ProviderTypeName: "swarm",
+	}
+	resp := &resource.MetadataResponse{}
+
+	r.Metadata(context.Background(), req, resp)
+
+	assert.Equal(t, "swarm_cluster", resp.TypeName)
+}
+
+func TestSwarmClusterResource_Schema(t *testing.T) {
+	r := NewSwarmClusterResource()
+
+	req := resource.SchemaRequest{}
+	resp := &resource.SchemaResponse{}
+
+	r.Schema(context.Background(), req, resp)
+
+	assert.NotNil(t, resp.Schema)
+	assert.Equal(t, "Build a whole Docker Swarm cluster from its manager and worker nodes.", resp.Schema.Description)
+
+	// Check required attributes exist
+	assert.Contains(t, resp.Schema.Attributes, "id")
+	assert.Contains(t, resp.Schema.Attributes, "managers")
+	assert.Contains(t, resp.Schema.Attributes, "workers")
+	assert.Contains(t, resp.Schema.Attributes, "node_ids")
+	assert.Contains(t, resp.Schema.Attributes, "manager_token")
+	assert.Contains(t, resp.Schema.Attributes, "worker_token")
+	assert.Contains(t, resp.Schema.Attributes, "node_timeout")
+	assert.Contains(t, resp.Schema.Attributes, "allow_quorum_loss")
+
+	// Verify sensitive attributes
+	managerToken := resp.Schema.Attributes["manager_token"]
+	assert.True(t, managerToken.(interface{ IsSensitive() bool }).IsSensitive())
+	workerToken := resp.Schema.Attributes["worker_token"]
+	assert.True(t, workerToken.(interface{ IsSensitive() bool }).IsSensitive())
+
+	// Members combine the Docker connection and the swarm settings
+	member := clusterMemberSchema()
+	assert.Contains(t, member.Attributes, "host")
+	assert.Contains(t, member.Attributes, "ssh_opts")
+	assert.Contains(t, member.Attributes, "advertise_addr")
+	assert.Contains(t, member.Attributes, "listen_addr")
+	assert.Contains(t, member.Attributes, "labels")
+}
+
+func TestSwarmClusterResource_Configure(t *testing.T) {
+	r := NewSwarmClusterResource().(resource.ResourceWithConfigure)
+
+	req := resource.ConfigureRequest{}
+	resp := &resource.ConfigureResponse{}
+
+	r.Configure(context.Background(), req, resp)
+
+	assert.False(t, resp.Diagnostics.HasError())
+}
+
+func TestSwarmClusterResource_InterfaceCompliance(t *testing.T) {
+	var _ resource.Resource = &swarmClusterResource{}
+	var _ resource.ResourceWithConfigure = &swarmClusterResource{}
+	var _ resource.ResourceWithValidateConfig = &swarmClusterResource{}
+	var _ resource.ResourceWithModifyPlan = &swarmClusterResource{}
+}
+
+func testClusterMember(host string) swarmClusterMemberModel {
+	return swarmClusterMemberModel{
+		Host:    tfTypes.StringValue(host),
+		Context: tfTypes.StringNull(),
+		Labels:  tfTypes.MapNull(tfTypes.StringType),
+	}
+}
+
+func TestPlanClusterChanges(t *testing.T) {
+	m1, m2, m3 := testClusterMember("ssh://m1"), testClusterMember("ssh://m2"), testClusterMember("ssh://m3")
+	w1, w2, w3 := testClusterMember("ssh://w1"), testClusterMember("ssh://w2"), testClusterMember("ssh://w3")
+
+	state := swarmClusterResourceModel{
+		Managers: []swarmClusterMemberModel{m1, m2},
+		Workers:  []swarmClusterMemberModel{w1, w2},
+	}
+	// m1 is replaced by m3, m2 is demoted, w1 is promoted, w2 leaves, w3 joins
+	plan := swarmClusterResourceModel{
+		Managers: []swarmClusterMemberModel{m3, w1},
+		Workers:  []swarmClusterMemberModel{m2, w3},
+	}
+
+	changes := planClusterChanges(&state, &plan)
+
+	assert.Empty(t, changes.staying)
+	assert.Equal(t, []swarmClusterMemberModel{m3}, changes.joinManagers)
+	assert.Equal(t, []swarmClusterMemberModel{w3}, changes.joinWorkers)
+	assert.Equal(t, []swarmClusterMemberModel{w1}, changes.promote)
+	assert.Equal(t, []swarmClusterMemberModel{m2}, changes.demote)
+	assert.Equal(t, []clusterMember{
+		{member: w2, role: swarm.NodeRoleWorker},
+		{member: m1, role: swarm.NodeRoleManager},
+	}, changes.remove)
+
+	// Nothing to do when the plan matches the state
+	changes = planClusterChanges(&state, &state)
+	assert.Equal(t, []swarmClusterMemberModel{m1, m2}, changes.staying)
+	assert.Empty(t, changes.joinManagers)
+	assert.Empty(t, changes.joinWorkers)
+	assert.Empty(t, changes.promote)
+	assert.Empty(t, changes.demote)
+	assert.Empty(t, changes.remove)
+}
+
+func TestCheckMemberAddrs(t *testing.T) {
+	m1, w1 := testClusterMember("ssh://m1"), testClusterMember("ssh://w1")
+	m1.AdvertiseAddr = tfTypes.StringValue("192.168.1.100")
+	w1.AdvertiseAddr = tfTypes.StringNull()
+	state := swarmClusterResourceModel{
+		Managers: []swarmClusterMemberModel{m1},
+		Workers:  []swarmClusterMemberModel{w1},
+	}
+
+	// Unchanged, or removed from the configuration
+	assert.False(t, checkMemberAddrs(&state, &state).HasError())
+	unset := m1
+	unset.AdvertiseAddr = tfTypes.StringNull()
+	assert.False(t, checkMemberAddrs(&state, &swarmClusterResourceModel{Managers: []swarmClusterMemberModel{unset}}).HasError())
+
+	// Changed on a manager moved to the workers, or set on a joined worker
+	moved := m1
+	moved.AdvertiseAddr = tfTypes.StringValue("192.168.1.200")
+	set := w1
+	set.ListenAddr = tfTypes.StringValue("0.0.0.0:2377")
+	diags := checkMemberAddrs(&state, &swarmClusterResourceModel{Workers: []swarmClusterMemberModel{moved, set}})
+	assert.Equal(t, 2, diags.ErrorsCount())
+
+	// New members can use any address
+	added := testClusterMember("ssh://m2")
+	added.AdvertiseAddr = tfTypes.StringValue("192.168.1.101")
+	assert.False(t, checkMemberAddrs(&state, &swarmClusterResourceModel{Managers: []swarmClusterMemberModel{m1, added}}).HasError())
+}
+
+func TestMergeLabels(t *testing.T) {
+	current := map[string]string{"zone": "a", "disk": "ssd", "external": "yes"}
+
+	labels, changed := mergeLabels(current, map[string]string{"zone": "a", "disk": "ssd"}, map[string]string{"zone": "b"})
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{"zone": "b", "external": "yes"}, labels)
+
+	labels, changed = mergeLabels(current, map[string]string{"zone": "a"}, map[string]string{"zone": "a"})
+	assert.False(t, changed)
+	assert.Equal(t, current, labels)
+}
+
+func TestFlattenClusterMembers(t *testing.T) {
+	m1, m2, w1 := testClusterMember("ssh://m1"), testClusterMember("ssh://m2"), testClusterMember("ssh://w1")
+	m1.Labels = tfTypes.MapValueMust(tfTypes.StringType, map[string]attr.Value{"zone": tfTypes.StringValue("a")})
+
+	model := swarmClusterResourceModel{
+		Managers: []swarmClusterMemberModel{m1, m2},
+		Workers:  []swarmClusterMemberModel{w1},
+	}
+	nodeIDs := map[string]string{"ssh://m1": "id-m1", "ssh://m2": "id-m2", "ssh://w1": "id-w1"}
+	// m2 was demoted and w1 left the swarm outside of Terraform
+	nodes := []swarm.Node{
+		{ID: "id-m1", Spec: swarm.NodeSpec{Role: swarm.NodeRoleManager, Annotations: swarm.Annotations{Labels: map[string]string{"zone": "b", "other": "x"}}}},
+		{ID: "id-m2", Spec: swarm.NodeSpec{Role: swarm.NodeRoleWorker}},
+	}
+
+	diags := flattenClusterMembers(context.Background(), nodes, &model, nodeIDs)
+
+	assert.False(t, diags.HasError())
+	assert.Len(t, model.Managers, 1)
+	assert.Equal(t, "ssh://m1", model.Managers[0].key())
+	assert.Equal(t, map[string]attr.Value{"zone": tfTypes.StringValue("b")}, model.Managers[0].Labels.Elements())
+	assert.Len(t, model.Workers, 1)
+	assert.Equal(t, "ssh://m2", model.Workers[0].key())
+	assert.NotContains(t, nodeIDs, "ssh://w1")
+}
@@ -2,13 +2,26 @@ package resources
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"time"
 
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/sntns/terraform-provider-swarm/internal/docker"
@@ -16,8 +29,10 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &swarmInitResource{}
-	_ resource.ResourceWithConfigure = &swarmInitResource{}
+	_ resource.Resource                   = &swarmInitResource{}
+	_ resource.ResourceWithConfigure      = &swarmInitResource{}
+	_ resource.ResourceWithImportState    = &swarmInitResource{}
+	_ resource.ResourceWithValidateConfig = &swarmInitResource{}
 )
 
 // NewSwarmInitResource is a helper function to simplify the provider implementation.
@@ -27,7 +42,8 @@ func NewSwarmInitResource() resource.Resource {
 
 // swarmInitResource is the resource implementation.
 type swarmInitResource struct {
-	client *client.Client
+	client       *client.Client
+	providerData *SwarmProviderData
 }
 
 // swarmInitResourceModel maps the resource schema data.
@@ -38,6 +54,38 @@ type swarmInitResourceModel struct {
 	ManagerToken  tfTypes.String      `tfsdk:"manager_token"`
 	WorkerToken   tfTypes.String      `tfsdk:"worker_token"`
 	Node          *swarmInitNodeModel `tfsdk:"node"`
+
+	TaskHistoryRetentionLimit tfTypes.Int64  `tfsdk:"task_history_retention_limit"`
+	SnapshotInterval          tfTypes.Int64  `tfsdk:"snapshot_interval"`
+	KeepOldSnapshots          tfTypes.Int64  `tfsdk:"keep_old_snapshots"`
+	HeartbeatPeriod           tfTypes.String `tfsdk:"heartbeat_period"`
+	ElectionTick              tfTypes.Int64  `tfsdk:"election_tick"`
+	HeartbeatTick             tfTypes.Int64  `tfsdk:"heartbeat_tick"`
+
+	RotateWorkerToken  tfTypes.String `tfsdk:"rotate_worker_token"`
+	RotateManagerToken tfTypes.String `tfsdk:"rotate_manager_token"`
+
+	Autolock        tfTypes.Bool   `tfsdk:"autolock"`
+	UnlockKey       tfTypes.String `tfsdk:"unlock_key"`
+	RotateUnlockKey tfTypes.String `tfsdk:"rotate_unlock_key"`
+
+	AllowQuorumLoss tfTypes.Bool `tfsdk:"allow_quorum_loss"`
+
+	DefaultAddrPool tfTypes.List   `tfsdk:"default_addr_pool"`
+	SubnetSize      tfTypes.Int64  `tfsdk:"subnet_size"`
+	DataPathPort    tfTypes.Int64  `tfsdk:"data_path_port"`
+	DataPathAddr    tfTypes.String `tfsdk:"data_path_addr"`
+	ForceNewCluster tfTypes.Bool   `tfsdk:"force_new_cluster"`
+	Availability    tfTypes.String `tfsdk:"availability"`
+
+	NodeCertExpiry tfTypes.String         `tfsdk:"node_cert_expiry"`
+	ExternalCAs    []swarmExternalCAModel `tfsdk:"external_ca"`
+	SigningCACert  tfTypes.String         `tfsdk:"signing_ca_cert"`
+	SigningCAKey   tfTypes.String         `tfsdk:"signing_ca_key"`
+	CARotation     *swarmCARotationModel  `tfsdk:"ca_rotation"`
+
+	AdoptExisting tfTypes.Bool   `tfsdk:"adopt_existing"`
+	ClusterID     tfTypes.String `tfsdk:"cluster_id"`
 }
 
 type swarmInitNodeModel struct {
@@ -48,6 +96,16 @@ type swarmInitNodeModel struct {
 	KeyMaterial  tfTypes.String `tfsdk:"key_material"`
 	CaMaterial   tfTypes.String `tfsdk:"ca_material"`
 	CertPath     tfTypes.String `tfsdk:"cert_path"`
+	SSH          *docker.TfSSH  `tfsdk:"ssh"`
+}
+
+// tfNode returns the node block as a docker.TfNode, nil when it is omitted.
+func (m *swarmInitNodeModel) tfNode() *docker.TfNode {
+	if m == nil {
+		return nil
+	}
+	node := docker.TfNode(*m)
+	return &node
 }
 
 // Metadata returns the resource type name.
@@ -71,10 +129,16 @@ func (r *swarmInitResource) Schema(_ context.Context, _ resource.SchemaRequest,
 			"advertise_addr": schema.StringAttribute{
 				Description: "Externally reachable address advertised to other nodes",
 				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfAddrChanged(),
+				},
 			},
 			"listen_addr": schema.StringAttribute{
 				Description: "Listen address for the raft consensus protocol",
 				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfAddrChanged(),
+				},
 			},
 			"manager_token": schema.StringAttribute{
 				Description: "Token for joining as a manager",
@@ -86,37 +150,263 @@ func (r *swarmInitResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Computed:    true,
 				Sensitive:   true,
 			},
+			"task_history_retention_limit": schema.Int64Attribute{
+				Description: "Number of historic tasks kept per instance or node. Negative values keep all tasks",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"snapshot_interval": schema.Int64Attribute{
+				Description: "Number of raft log entries between snapshots",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"keep_old_snapshots": schema.Int64Attribute{
+				Description: "Number of raft snapshots kept beyond the current one",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"heartbeat_period": schema.StringAttribute{
+				Description: "Period at which nodes send heartbeats to the dispatcher (e.g. \"5s\")",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"election_tick": schema.Int64Attribute{
+				Description: "Number of ticks a follower waits without leader message before starting an election",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"heartbeat_tick": schema.Int64Attribute{
+				Description: "Number of ticks between raft heartbeats sent by the leader",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"rotate_worker_token": schema.StringAttribute{
+				Description: "Arbitrary value (e.g. a counter or a date); changing it rotates the worker join token",
+				Optional:    true,
+			},
+			"rotate_manager_token": schema.StringAttribute{
+				Description: "Arbitrary value (e.g. a counter or a date); changing it rotates the manager join token",
+				Optional:    true,
+			},
+			"autolock": schema.BoolAttribute{
+				Description: "Encrypt the raft logs and manager TLS keys at rest; managers must be unlocked after a restart",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"unlock_key": schema.StringAttribute{
+				Description: "Key used to unlock managers when autolock is enabled",
+				Computed:    true,
+				Sensitive:   true,
+			},
+			"rotate_unlock_key": schema.StringAttribute{
+				Description: "Arbitrary value (e.g. a counter or a date); changing it rotates the unlock key",
+				Optional:    true,
+			},
+			"default_addr_pool": schema.ListAttribute{
+				Description: "Address pools (CIDR) from which overlay network subnets are allocated",
+				ElementType: tfTypes.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+					listplanmodifier.RequiresReplaceIfConfigured(),
+				},
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(cidrValidator{}),
+				},
+			},
+			"subnet_size": schema.Int64Attribute{
+				Description: "Prefix length of the subnets allocated from the default address pools",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+				Validators: []validator.Int64{
+					int64validator.Between(1, 29),
+				},
+			},
+			"data_path_port": schema.Int64Attribute{
+				Description: "UDP port used for overlay network (VXLAN) traffic",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+					int64planmodifier.RequiresReplaceIfConfigured(),
+				},
+				Validators: []validator.Int64{
+					int64validator.Between(1024, 49151),
+				},
+			},
+			"data_path_addr": schema.StringAttribute{
+				Description: "Address or interface used for overlay network (VXLAN) traffic",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIfConfigured(),
+				},
+			},
+			"force_new_cluster": schema.BoolAttribute{
+				Description: "Force the creation of a new single manager cluster from the current state of the node",
+				Optional:    true,
+			},
+			"availability": schema.StringAttribute{
+				Description: "Availability of the bootstrap node (active, pause or drain)",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(swarm.NodeAvailabilityActive),
+						string(swarm.NodeAvailabilityPause),
+						string(swarm.NodeAvailabilityDrain),
+					),
+				},
+			},
+			"node_cert_expiry": schema.StringAttribute{
+				Description: "Validity period of the node certificates (e.g. \"168h\")",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"external_ca": schema.ListNestedAttribute{
+				Description: "External certificate authorities issuing the node certificates",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"protocol": schema.StringAttribute{
+							Description: "Protocol used to talk to the external CA",
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString(string(swarm.ExternalCAProtocolCFSSL)),
+							Validators: []validator.String{
+								stringvalidator.OneOf(string(swarm.ExternalCAProtocolCFSSL)),
+							},
+						},
+						"url": schema.StringAttribute{
+							Description: "URL of the external CA signing endpoint",
+							Required:    true,
+						},
+						"options": schema.MapAttribute{
+							Description: "Options passed to the external CA",
+							ElementType: tfTypes.StringType,
+							Optional:    true,
+						},
+						"ca_cert": schema.StringAttribute{
+							Description: "PEM-encoded root certificate of the external CA",
+							Optional:    true,
+							Validators: []validator.String{
+								pemValidator{},
+							},
+						},
+					},
+				},
+			},
+			"signing_ca_cert": schema.StringAttribute{
+				Description: "PEM-encoded CA certificate used by the swarm to sign node certificates",
+				Optional:    true,
+				Sensitive:   true,
+				Validators: []validator.String{
+					pemValidator{},
+					stringvalidator.AlsoRequires(path.MatchRoot("signing_ca_key")),
+				},
+			},
+			"signing_ca_key": schema.StringAttribute{
+				Description: "PEM-encoded private key of the signing CA certificate",
+				Optional:    true,
+				Sensitive:   true,
+				Validators: []validator.String{
+					pemValidator{},
+					stringvalidator.AlsoRequires(path.MatchRoot("signing_ca_cert")),
+				},
+			},
+			"ca_rotation": schema.SingleNestedAttribute{
+				Description: "Root CA rotation of the swarm",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"trigger": schema.StringAttribute{
+						Description: "Arbitrary value (e.g. a counter or a date); changing it rotates the root CA",
+						Optional:    true,
+					},
+					"timeout": schema.StringAttribute{
+						Description: "Maximum time to wait for every node to trust the new root CA",
+						Optional:    true,
+						Computed:    true,
+						Default:     stringdefault.StaticString(defaultCARotationTimeout),
+						Validators: []validator.String{
+							durationValidator{},
+						},
+					},
+				},
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Description: "Take over the swarm the node already manages instead of failing to initialize a new one",
+				Optional:    true,
+			},
+			"cluster_id": schema.StringAttribute{
+				Description: "Expected ID of the swarm taken over with adopt_existing",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("adopt_existing")),
+				},
+			},
+			"allow_quorum_loss": schema.BoolAttribute{
+				Description: "Leave the swarm on destroy even when the remaining managers lose the quorum or are left without a manager",
+				Optional:    true,
+			},
 		},
 	}
 }
 
-// Plus de configuration à faire côté provider
+// Configure keeps the provider default connection, used when node is omitted.
 func (r *swarmInitResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, diags := ConfiguredProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	r.providerData = providerData
 }
 
 // Create creates the resource and sets the initial Terraform state.
 func (r *swarmInitResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan struct {
-		Node          docker.TfNode  `tfsdk:"node"`
-		AdvertiseAddr tfTypes.String `tfsdk:"advertise_addr"`
-		ListenAddr    tfTypes.String `tfsdk:"listen_addr"`
-		ID            tfTypes.String `tfsdk:"id"`
-		ManagerToken  tfTypes.String `tfsdk:"manager_token"`
-		WorkerToken   tfTypes.String `tfsdk:"worker_token"`
-	}
+	var plan swarmInitResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Use shared extraction logic
-	dockerConfig := docker.ExtractConfig(plan.Node)
-	tflog.Debug(ctx, "Docker client config", map[string]interface{}{
-		"host":     dockerConfig.Host,
-		"ssh_opts": dockerConfig.SSHOpts,
-	})
-	dockerClient, err := dockerConfig.NewClient()
+	// Use the node block, or the provider connection when it is omitted
+	dockerClient, err := r.providerData.NewClient(plan.Node.tfNode())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Docker Client",
@@ -126,7 +416,62 @@ func (r *swarmInitResource) Create(ctx context.Context, req resource.CreateReque
 	}
 	r.client = dockerClient
 
-	// Initialize swarm
+	nodeInfo, err := r.client.Info(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting node info",
+			"Could not get node info before initializing swarm, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	if nodeInfo.Swarm.LocalNodeState != swarm.LocalNodeStateInactive {
+		if !plan.AdoptExisting.ValueBool() {
+			resp.Diagnostics.AddError(
+				"Node Already Part of a Swarm",
+				"The node is already part of a swarm (state: "+string(nodeInfo.Swarm.LocalNodeState)+"). "+
+					"Set adopt_existing to true to take over the existing swarm, or import it.",
+			)
+			return
+		}
+		resp.Diagnostics.Append(r.adopt(ctx, nodeInfo.Swarm, &plan)...)
+	} else {
+		resp.Diagnostics.Append(r.initialize(ctx, &plan)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	swarmInfo, err := r.client.SwarmInspect(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error inspecting swarm",
+			"Could not inspect swarm after initialization, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = tfTypes.StringValue(swarmInfo.ID)
+	plan.ManagerToken = tfTypes.StringValue(swarmInfo.JoinTokens.Manager)
+	plan.WorkerToken = tfTypes.StringValue(swarmInfo.JoinTokens.Worker)
+	flattenClusterSpec(swarmInfo.Spec, &plan)
+	resp.Diagnostics.Append(flattenInitNetwork(ctx, swarmInfo.ClusterInfo, &plan)...)
+	resp.Diagnostics.Append(flattenCAConfig(ctx, swarmInfo.Spec, &plan)...)
+	resp.Diagnostics.Append(readUnlockKey(ctx, r.client, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// initialize creates a new swarm with the settings of the model.
+func (r *swarmInitResource) initialize(ctx context.Context, plan *swarmInitResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
 	initRequest := swarm.InitRequest{}
 	if !plan.AdvertiseAddr.IsNull() {
 		initRequest.AdvertiseAddr = plan.AdvertiseAddr.ValueString()
@@ -134,54 +479,108 @@ func (r *swarmInitResource) Create(ctx context.Context, req resource.CreateReque
 	if !plan.ListenAddr.IsNull() {
 		initRequest.ListenAddr = plan.ListenAddr.ValueString()
 	}
+	diags.Append(expandClusterSpec(plan, &initRequest.Spec)...)
+	if diags.HasError() {
+		return diags
+	}
+	initRequest.AutoLockManagers = initRequest.Spec.EncryptionConfig.AutoLockManagers
+	diags.Append(expandCAConfig(ctx, plan, &initRequest.Spec)...)
+	if diags.HasError() {
+		return diags
+	}
+	if isKnown(plan.SigningCACert) && isKnown(plan.SigningCAKey) {
+		initRequest.Spec.CAConfig.SigningCACert = plan.SigningCACert.ValueString()
+		initRequest.Spec.CAConfig.SigningCAKey = plan.SigningCAKey.ValueString()
+	}
+	diags.Append(expandInitNetwork(ctx, plan, &initRequest)...)
+	if diags.HasError() {
+		return diags
+	}
 	nodeID, err := r.client.SwarmInit(ctx, initRequest)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Error initializing swarm",
 			"Could not initialize swarm, unexpected error: "+err.Error(),
 		)
-		return
+		return diags
 	}
 	tflog.Trace(ctx, "initialized swarm", map[string]interface{}{
 		"node_id": nodeID,
 	})
+	return diags
+}
+
+// adopt takes over the swarm the node already manages, checking that it is
+// the expected one, and applies the settings of the model to it.
+func (r *swarmInitResource) adopt(ctx context.Context, info swarm.Info, plan *swarmInitResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if info.LocalNodeState == swarm.LocalNodeStateLocked {
+		diags.AddError(
+			"Swarm Manager Locked",
+			"The node manages a locked swarm and must be unlocked (see swarm_unlock) before it can be adopted.",
+		)
+		return diags
+	}
+	if info.LocalNodeState != swarm.LocalNodeStateActive || !info.ControlAvailable {
+		diags.AddError(
+			"Node Is Not a Swarm Manager",
+			"Only a manager of an active swarm can be adopted, the node is "+string(info.LocalNodeState)+
+				" and manager: "+fmt.Sprint(info.ControlAvailable)+".",
+		)
+		return diags
+	}
+
 	swarmInfo, err := r.client.SwarmInspect(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"Error inspecting swarm",
-			"Could not inspect swarm after initialization, unexpected error: "+err.Error(),
+			"Could not inspect the swarm to adopt, unexpected error: "+err.Error(),
 		)
-		return
+		return diags
 	}
-	swarmInfoWithTokens, err := r.client.SwarmInspect(ctx)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error getting join tokens from swarm inspect",
-			"Could not get join tokens from swarm inspect, unexpected error: "+err.Error(),
+	if isKnown(plan.ClusterID) && plan.ClusterID.ValueString() != swarmInfo.ID {
+		diags.AddError(
+			"Swarm Mismatch",
+			"The node manages swarm "+swarmInfo.ID+" while cluster_id expects "+plan.ClusterID.ValueString()+".",
 		)
-		return
+		return diags
 	}
-	state := swarmInitResourceModel{
-		ID:            tfTypes.StringValue(swarmInfo.ID),
-		AdvertiseAddr: plan.AdvertiseAddr,
-		ListenAddr:    plan.ListenAddr,
-		ManagerToken:  tfTypes.StringValue(swarmInfoWithTokens.JoinTokens.Manager),
-		WorkerToken:   tfTypes.StringValue(swarmInfoWithTokens.JoinTokens.Worker),
-		Node: &swarmInitNodeModel{
-			Host:         plan.Node.Host,
-			Context:      plan.Node.Context,
-			SSHOpts:      plan.Node.SSHOpts,
-			CertMaterial: plan.Node.CertMaterial,
-			KeyMaterial:  plan.Node.KeyMaterial,
-			CaMaterial:   plan.Node.CaMaterial,
-			CertPath:     plan.Node.CertPath,
-		},
+	diags.Append(checkInitNetwork(ctx, plan, swarmInfo.ClusterInfo)...)
+	if diags.HasError() {
+		return diags
 	}
-	diags = resp.State.Set(ctx, &state)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+
+	// Settings that can change in place are applied like in Update
+	spec := swarmInfo.Spec
+	diags.Append(expandClusterSpec(plan, &spec)...)
+	diags.Append(expandCAConfig(ctx, plan, &spec)...)
+	if diags.HasError() {
+		return diags
+	}
+	if isKnown(plan.SigningCACert) && isKnown(plan.SigningCAKey) {
+		spec.CAConfig.SigningCACert = plan.SigningCACert.ValueString()
+		spec.CAConfig.SigningCAKey = plan.SigningCAKey.ValueString()
+	}
+	err = r.client.SwarmUpdate(ctx, swarmInfo.Version, spec, swarm.UpdateFlags{})
+	if err != nil {
+		diags.AddError(
+			"Error updating swarm",
+			"Could not apply the configuration to adopted swarm "+swarmInfo.ID+", unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	if isKnown(plan.Availability) {
+		diags.Append(setLocalAvailability(ctx, r.client, swarm.NodeAvailability(plan.Availability.ValueString()))...)
+		if diags.HasError() {
+			return diags
+		}
 	}
+
+	tflog.Trace(ctx, "adopted swarm", map[string]interface{}{
+		"swarm_id": swarmInfo.ID,
+	})
+	return diags
 }
 
 // Read refreshes the Terraform state with the latest data.
@@ -194,21 +593,7 @@ func (r *swarmInitResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	// Recreate Docker client from state.Node
-	var sshOpts []string
-	if state.Node != nil && state.Node.SSHOpts.Elements() != nil {
-		for _, opt := range state.Node.SSHOpts.Elements() {
-			sshOpts = append(sshOpts, opt.String())
-		}
-	}
-	dockerConfig := &docker.Config{
-		Host:     state.Node.Host.ValueString(),
-		SSHOpts:  sshOpts,
-		Cert:     state.Node.CertMaterial.ValueString(),
-		Key:      state.Node.KeyMaterial.ValueString(),
-		Ca:       state.Node.CaMaterial.ValueString(),
-		CertPath: state.Node.CertPath.ValueString(),
-	}
-	dockerClient, err := dockerConfig.NewClient()
+	dockerClient, err := r.providerData.NewClient(state.Node.tfNode())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Docker Client in Read",
@@ -217,6 +602,35 @@ func (r *swarmInitResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	nodeInfo, err := dockerClient.Info(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Node Info",
+			"Could not read node info: "+err.Error(),
+		)
+		return
+	}
+	// A node that left the swarm, or was demoted, no longer holds this swarm;
+	// a locked manager still does and is handled below
+	if nodeInfo.Swarm.LocalNodeState == swarm.LocalNodeStateInactive ||
+		(nodeInfo.Swarm.LocalNodeState == swarm.LocalNodeStateActive && !nodeInfo.Swarm.ControlAvailable) {
+		resp.State.RemoveResource(ctx)
+		resp.Diagnostics.AddWarning(
+			"Node Left the Swarm",
+			"The node is no longer a manager of swarm "+state.ID.ValueString()+". The resource will be recreated.",
+		)
+		return
+	}
+	// The swarm API of a locked manager is unavailable until it is unlocked,
+	// keep the last known state rather than reading it as gone
+	if nodeInfo.Swarm.LocalNodeState == swarm.LocalNodeStateLocked {
+		resp.Diagnostics.AddWarning(
+			"Swarm Manager Locked",
+			"The node is a locked manager of swarm "+state.ID.ValueString()+" and must be unlocked (see swarm_unlock) before it can be refreshed. The last known state is kept.",
+		)
+		return
+	}
+
 	// Get current swarm info
 	swarmInfo, err := dockerClient.SwarmInspect(ctx)
 	if err != nil {
@@ -233,6 +647,30 @@ func (r *swarmInitResource) Read(ctx context.Context, req resource.ReadRequest,
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	if !state.ID.IsNull() && state.ID.ValueString() != swarmInfo.ID {
+		resp.State.RemoveResource(ctx)
+		resp.Diagnostics.AddWarning(
+			"Swarm Re-initialized",
+			"The node now manages swarm "+swarmInfo.ID+" instead of "+state.ID.ValueString()+". The resource will be recreated.",
+		)
+		return
+	}
+
+	// A node now advertising another address shows up as a replacement
+	node, _, err := dockerClient.NodeInspectWithRaw(ctx, nodeInfo.Swarm.NodeID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error inspecting node",
+			"Could not inspect node "+nodeInfo.Swarm.NodeID+": "+err.Error(),
+		)
+		return
+	}
+	raftAddr := nodeInfo.Swarm.NodeAddr
+	if node.ManagerStatus != nil {
+		raftAddr = node.ManagerStatus.Addr
+	}
+	state.ListenAddr = refreshListenAddr(state.ListenAddr, state.AdvertiseAddr, raftAddr)
+	state.AdvertiseAddr = refreshAddr(state.AdvertiseAddr, raftAddr)
 
 	// Update state with current swarm info
 	state.ID = tfTypes.StringValue(swarmInfo.ID)
@@ -241,6 +679,15 @@ func (r *swarmInitResource) Read(ctx context.Context, req resource.ReadRequest,
 	state.ManagerToken = tfTypes.StringValue(swarmInfo.JoinTokens.Manager)
 	state.WorkerToken = tfTypes.StringValue(swarmInfo.JoinTokens.Worker)
 
+	// Refresh cluster settings so that out-of-band changes show up as drift
+	flattenClusterSpec(swarmInfo.Spec, &state)
+	resp.Diagnostics.Append(flattenInitNetwork(ctx, swarmInfo.ClusterInfo, &state)...)
+	resp.Diagnostics.Append(flattenCAConfig(ctx, swarmInfo.Spec, &state)...)
+	resp.Diagnostics.Append(readUnlockKey(ctx, dockerClient, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Set refreshed state
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -251,11 +698,123 @@ func (r *swarmInitResource) Read(ctx context.Context, req resource.ReadRequest,
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *swarmInitResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Most swarm init settings cannot be updated after creation
-	resp.Diagnostics.AddError(
-		"Swarm Init Update Not Supported",
-		"Swarm initialization settings cannot be updated after creation. Consider recreating the resource.",
-	)
+	var plan, state swarmInitResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dockerClient, err := r.providerData.NewClient(plan.Node.tfNode())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client in Update",
+			"An unexpected error occurred when creating the Docker client in Update. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// Cluster settings are updated on top of the current spec and version
+	swarmInfo, err := dockerClient.SwarmInspect(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error inspecting swarm",
+			"Could not inspect swarm before update, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	spec := swarmInfo.Spec
+	resp.Diagnostics.Append(expandClusterSpec(&plan, &spec)...)
+	resp.Diagnostics.Append(expandCAConfig(ctx, &plan, &spec)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// New signing material, or a changed rotation trigger, makes the
+	// managers rotate the root CA
+	rotateCA := false
+	if isKnown(plan.SigningCACert) && isKnown(plan.SigningCAKey) &&
+		(!plan.SigningCACert.Equal(state.SigningCACert) || !plan.SigningCAKey.Equal(state.SigningCAKey)) {
+		spec.CAConfig.SigningCACert = plan.SigningCACert.ValueString()
+		spec.CAConfig.SigningCAKey = plan.SigningCAKey.ValueString()
+		rotateCA = true
+	}
+	if !caRotationTrigger(plan.CARotation).Equal(caRotationTrigger(state.CARotation)) {
+		spec.CAConfig.ForceRotate = swarmInfo.Spec.CAConfig.ForceRotate + 1
+		rotateCA = true
+	}
+	// Changing a rotation trigger asks the managers for a new join token
+	flags := swarm.UpdateFlags{
+		RotateWorkerToken:      !plan.RotateWorkerToken.Equal(state.RotateWorkerToken),
+		RotateManagerToken:     !plan.RotateManagerToken.Equal(state.RotateManagerToken),
+		RotateManagerUnlockKey: !plan.RotateUnlockKey.Equal(state.RotateUnlockKey) && spec.EncryptionConfig.AutoLockManagers,
+	}
+	err = dockerClient.SwarmUpdate(ctx, swarmInfo.Version, spec, flags)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating swarm",
+			"Could not update swarm "+swarmInfo.ID+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	tflog.Trace(ctx, "updated swarm", map[string]interface{}{
+		"version":               swarmInfo.Version.Index,
+		"rotated_worker_token":  flags.RotateWorkerToken,
+		"rotated_manager_token": flags.RotateManagerToken,
+		"rotated_unlock_key":    flags.RotateManagerUnlockKey,
+		"rotated_root_ca":       rotateCA,
+	})
+
+	if rotateCA {
+		timeout, err := time.ParseDuration(caRotationTimeout(plan.CARotation))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid ca_rotation timeout",
+				"Could not parse the ca_rotation timeout as a duration: "+err.Error(),
+			)
+			return
+		}
+		resp.Diagnostics.Append(waitForCARotation(ctx, dockerClient, timeout)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// The availability of the bootstrap node is the only network default
+	// that can change without initializing a new swarm
+	if isKnown(plan.Availability) && !plan.Availability.Equal(state.Availability) {
+		resp.Diagnostics.Append(setLocalAvailability(ctx, dockerClient, swarm.NodeAvailability(plan.Availability.ValueString()))...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	swarmInfo, err = dockerClient.SwarmInspect(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error inspecting swarm",
+			"Could not inspect swarm after update, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = tfTypes.StringValue(swarmInfo.ID)
+	plan.ManagerToken = tfTypes.StringValue(swarmInfo.JoinTokens.Manager)
+	plan.WorkerToken = tfTypes.StringValue(swarmInfo.JoinTokens.Worker)
+	flattenClusterSpec(swarmInfo.Spec, &plan)
+	resp.Diagnostics.Append(flattenInitNetwork(ctx, swarmInfo.ClusterInfo, &plan)...)
+	resp.Diagnostics.Append(flattenCAConfig(ctx, swarmInfo.Spec, &plan)...)
+	resp.Diagnostics.Append(readUnlockKey(ctx, dockerClient, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -269,21 +828,7 @@ func (r *swarmInitResource) Delete(ctx context.Context, req resource.DeleteReque
 
 	// Recreate Docker client from state.Node if needed
 	if r.client == nil {
-		var sshOpts []string
-		if state.Node != nil && state.Node.SSHOpts.Elements() != nil {
-			for _, opt := range state.Node.SSHOpts.Elements() {
-				sshOpts = append(sshOpts, opt.String())
-			}
-		}
-		dockerConfig := &docker.Config{
-			Host:     state.Node.Host.ValueString(),
-			SSHOpts:  sshOpts,
-			Cert:     state.Node.CertMaterial.ValueString(),
-			Key:      state.Node.KeyMaterial.ValueString(),
-			Ca:       state.Node.CaMaterial.ValueString(),
-			CertPath: state.Node.CertPath.ValueString(),
-		}
-		dockerClient, err := dockerConfig.NewClient()
+		dockerClient, err := r.providerData.NewClient(state.Node.tfNode())
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Unable to Create Docker Client in Delete",
@@ -294,6 +839,25 @@ func (r *swarmInitResource) Delete(ctx context.Context, req resource.DeleteReque
 		r.client = dockerClient
 	}
 
+	// A forced leave keeps the manager in the raft membership, make sure
+	// the other managers keep the quorum without it
+	if !state.AllowQuorumLoss.ValueBool() {
+		nodeInfo, err := r.client.Info(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error getting node info",
+				"Could not get node info before leaving the swarm, unexpected error: "+err.Error(),
+			)
+			return
+		}
+		if nodeInfo.Swarm.ControlAvailable {
+			resp.Diagnostics.Append(checkManagerRemoval(ctx, r.client, nodeInfo.Swarm.NodeID, false)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
 	// Leave the swarm (force leave to ensure it works)
 	err := r.client.SwarmLeave(ctx, true)
 	if err != nil {
@@ -304,3 +868,319 @@ func (r *swarmInitResource) Delete(ctx context.Context, req resource.DeleteReque
 		return
 	}
 }
+
+// ImportState adopts an existing swarm from the manager described by the import ID.
+func (r *swarmInitResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	node, err := parseNodeImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			err.Error(),
+		)
+		return
+	}
+
+	dockerClient, err := r.providerData.NewClient(&node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client in Import",
+			"An unexpected error occurred when creating the Docker client in Import. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	nodeInfo, err := dockerClient.Info(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Node Info",
+			"Could not read node info: "+err.Error(),
+		)
+		return
+	}
+	if !nodeInfo.Swarm.ControlAvailable {
+		resp.Diagnostics.AddError(
+			"Node Is Not a Swarm Manager",
+			"The node at "+node.ID()+" is not a manager of an active swarm (state: "+string(nodeInfo.Swarm.LocalNodeState)+").",
+		)
+		return
+	}
+
+	swarmInfo, err := dockerClient.SwarmInspect(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error inspecting swarm",
+			"Could not inspect swarm during import, unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	nodeModel := swarmInitNodeModel(node)
+	state := swarmInitResourceModel{
+		ID:                 tfTypes.StringValue(swarmInfo.ID),
+		AdvertiseAddr:      tfTypes.StringNull(),
+		ListenAddr:         tfTypes.StringNull(),
+		ManagerToken:       tfTypes.StringValue(swarmInfo.JoinTokens.Manager),
+		WorkerToken:        tfTypes.StringValue(swarmInfo.JoinTokens.Worker),
+		Node:               &nodeModel,
+		HeartbeatPeriod:    tfTypes.StringNull(),
+		RotateWorkerToken:  tfTypes.StringNull(),
+		RotateManagerToken: tfTypes.StringNull(),
+		RotateUnlockKey:    tfTypes.StringNull(),
+		AllowQuorumLoss:    tfTypes.BoolNull(),
+		DataPathAddr:       tfTypes.StringNull(),
+		ForceNewCluster:    tfTypes.BoolNull(),
+		Availability:       tfTypes.StringNull(),
+		NodeCertExpiry:     tfTypes.StringNull(),
+		SigningCACert:      tfTypes.StringNull(),
+		SigningCAKey:       tfTypes.StringNull(),
+		AdoptExisting:      tfTypes.BoolNull(),
+		ClusterID:          tfTypes.StringNull(),
+	}
+	flattenClusterSpec(swarmInfo.Spec, &state)
+	resp.Diagnostics.Append(flattenInitNetwork(ctx, swarmInfo.ClusterInfo, &state)...)
+	resp.Diagnostics.Append(flattenCAConfig(ctx, swarmInfo.Spec, &state)...)
+	resp.Diagnostics.Append(readUnlockKey(ctx, dockerClient, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "imported swarm", map[string]interface{}{
+		"swarm_id": swarmInfo.ID,
+	})
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// expandClusterSpec applies the cluster settings set in the model on top of spec.
+// Unset settings keep the value already present in spec.
+func expandClusterSpec(model *swarmInitResourceModel, spec *swarm.Spec) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if isKnown(model.TaskHistoryRetentionLimit) {
+		limit := model.TaskHistoryRetentionLimit.ValueInt64()
+		spec.Orchestration.TaskHistoryRetentionLimit = &limit
+	}
+	if isKnown(model.SnapshotInterval) {
+		spec.Raft.SnapshotInterval = uint64(model.SnapshotInterval.ValueInt64())
+	}
+	if isKnown(model.KeepOldSnapshots) {
+		keep := uint64(model.KeepOldSnapshots.ValueInt64())
+		spec.Raft.KeepOldSnapshots = &keep
+	}
+	if isKnown(model.ElectionTick) {
+		spec.Raft.ElectionTick = int(model.ElectionTick.ValueInt64())
+	}
+	if isKnown(model.HeartbeatTick) {
+		spec.Raft.HeartbeatTick = int(model.HeartbeatTick.ValueInt64())
+	}
+	if isKnown(model.Autolock) {
+		spec.EncryptionConfig.AutoLockManagers = model.Autolock.ValueBool()
+	}
+	if isKnown(model.HeartbeatPeriod) {
+		period, err := time.ParseDuration(model.HeartbeatPeriod.ValueString())
+		if err != nil {
+			diags.AddError(
+				"Invalid heartbeat_period",
+				fmt.Sprintf("Could not parse heartbeat_period %q as a duration: %s", model.HeartbeatPeriod.ValueString(), err),
+			)
+		}
+		spec.Dispatcher.HeartbeatPeriod = period
+	}
+
+	return diags
+}
+
+// flattenClusterSpec copies the cluster settings of spec into the model.
+func flattenClusterSpec(spec swarm.Spec, model *swarmInitResourceModel) {
+	model.TaskHistoryRetentionLimit = tfTypes.Int64Null()
+	if spec.Orchestration.TaskHistoryRetentionLimit != nil {
+		model.TaskHistoryRetentionLimit = tfTypes.Int64Value(*spec.Orchestration.TaskHistoryRetentionLimit)
+	}
+	model.SnapshotInterval = tfTypes.Int64Value(int64(spec.Raft.SnapshotInterval))
+	model.KeepOldSnapshots = tfTypes.Int64Value(0)
+	if spec.Raft.KeepOldSnapshots != nil {
+		model.KeepOldSnapshots = tfTypes.Int64Value(int64(*spec.Raft.KeepOldSnapshots))
+	}
+	model.ElectionTick = tfTypes.Int64Value(int64(spec.Raft.ElectionTick))
+	model.HeartbeatTick = tfTypes.Int64Value(int64(spec.Raft.HeartbeatTick))
+	model.Autolock = tfTypes.BoolValue(spec.EncryptionConfig.AutoLockManagers)
+	model.HeartbeatPeriod = flattenDuration(model.HeartbeatPeriod, spec.Dispatcher.HeartbeatPeriod)
+}
+
+// ValidateConfig checks that the subnets fit in the default address pools.
+func (r *swarmInitResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config swarmInitResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !isKnown(config.SubnetSize) || !isKnown(config.DefaultAddrPool) {
+		return
+	}
+
+	for _, element := range config.DefaultAddrPool.Elements() {
+		pool, ok := element.(tfTypes.String)
+		if !ok || !isKnown(pool) {
+			continue
+		}
+		_, network, err := net.ParseCIDR(pool.ValueString())
+		if err != nil {
+			// Reported by the attribute validator
+			continue
+		}
+		prefix, _ := network.Mask.Size()
+		if config.SubnetSize.ValueInt64() < int64(prefix) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("subnet_size"),
+				"Invalid Subnet Size",
+				fmt.Sprintf("subnet_size %d is larger than the address pool %s, use a prefix length of at least %d.",
+					config.SubnetSize.ValueInt64(), pool.ValueString(), prefix),
+			)
+		}
+	}
+}
+
+// expandInitNetwork copies the network defaults and initial node settings of
+// the model into the init request.
+func expandInitNetwork(ctx context.Context, model *swarmInitResourceModel, initRequest *swarm.InitRequest) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if isKnown(model.DefaultAddrPool) {
+		diags.Append(model.DefaultAddrPool.ElementsAs(ctx, &initRequest.DefaultAddrPool, false)...)
+	}
+	if isKnown(model.SubnetSize) {
+		initRequest.SubnetSize = uint32(model.SubnetSize.ValueInt64())
+	}
+	if isKnown(model.DataPathPort) {
+		initRequest.DataPathPort = uint32(model.DataPathPort.ValueInt64())
+	}
+	if isKnown(model.DataPathAddr) {
+		initRequest.DataPathAddr = model.DataPathAddr.ValueString()
+	}
+	if isKnown(model.ForceNewCluster) {
+		initRequest.ForceNewCluster = model.ForceNewCluster.ValueBool()
+	}
+	if isKnown(model.Availability) {
+		initRequest.Availability = swarm.NodeAvailability(model.Availability.ValueString())
+	}
+
+	return diags
+}
+
+// checkInitNetwork reports the configured network defaults that differ from
+// the ones of an existing swarm, since they can only be set at initialization.
+func checkInitNetwork(ctx context.Context, model *swarmInitResourceModel, info swarm.ClusterInfo) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	actual := swarmInitResourceModel{}
+	diags.Append(flattenInitNetwork(ctx, info, &actual)...)
+	if diags.HasError() {
+		return diags
+	}
+	if isKnown(model.DefaultAddrPool) && !model.DefaultAddrPool.Equal(actual.DefaultAddrPool) {
+		diags.AddAttributeError(
+			path.Root("default_addr_pool"),
+			"Swarm Settings Mismatch",
+			"The existing swarm uses the address pools "+actual.DefaultAddrPool.String()+", which cannot be changed after initialization.",
+		)
+	}
+	if isKnown(model.SubnetSize) && !model.SubnetSize.Equal(actual.SubnetSize) {
+		diags.AddAttributeError(
+			path.Root("subnet_size"),
+			"Swarm Settings Mismatch",
+			"The existing swarm uses a subnet size of "+actual.SubnetSize.String()+", which cannot be changed after initialization.",
+		)
+	}
+	if isKnown(model.DataPathPort) && !model.DataPathPort.Equal(actual.DataPathPort) {
+		diags.AddAttributeError(
+			path.Root("data_path_port"),
+			"Swarm Settings Mismatch",
+			"The existing swarm uses the data path port "+actual.DataPathPort.String()+", which cannot be changed after initialization.",
+		)
+	}
+	return diags
+}
+
+// flattenInitNetwork copies the network defaults of the cluster into the model.
+func flattenInitNetwork(ctx context.Context, info swarm.ClusterInfo, model *swarmInitResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	pools := info.DefaultAddrPool
+	if pools == nil {
+		pools = []string{}
+	}
+	model.DefaultAddrPool, diags = tfTypes.ListValueFrom(ctx, tfTypes.StringType, pools)
+	model.SubnetSize = tfTypes.Int64Value(int64(info.SubnetSize))
+	model.DataPathPort = tfTypes.Int64Value(int64(info.DataPathPort))
+	return diags
+}
+
+// setLocalAvailability changes the availability of the node the client is connected to.
+func setLocalAvailability(ctx context.Context, dockerClient *client.Client, availability swarm.NodeAvailability) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	nodeInfo, err := dockerClient.Info(ctx)
+	if err != nil {
+		diags.AddError(
+			"Error getting node info",
+			"Could not get node info before changing its availability, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	nodeID := nodeInfo.Swarm.NodeID
+	node, _, err := dockerClient.NodeInspectWithRaw(ctx, nodeID)
+	if err != nil {
+		diags.AddError(
+			"Error inspecting node",
+			"Could not inspect node "+nodeID+", unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	spec := node.Spec
+	spec.Availability = availability
+	err = dockerClient.NodeUpdate(ctx, nodeID, node.Version, spec)
+	if err != nil {
+		diags.AddError(
+			"Error updating node availability",
+			"Could not set the availability of node "+nodeID+" to "+string(availability)+", unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+
+	tflog.Trace(ctx, "changed node availability", map[string]interface{}{
+		"node_id":      nodeID,
+		"availability": string(availability),
+	})
+	return diags
+}
+
+// readUnlockKey stores the current unlock key of the swarm in the model.
+// The key is empty when autolock is disabled.
+func readUnlockKey(ctx context.Context, dockerClient *client.Client, model *swarmInitResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	model.UnlockKey = tfTypes.StringValue("")
+	if !model.Autolock.ValueBool() {
+		return diags
+	}
+	unlockKey, err := dockerClient.SwarmGetUnlockKey(ctx)
+	if err != nil {
+		diags.AddError(
+			"Error getting unlock key",
+			"Could not get the swarm unlock key, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	model.UnlockKey = tfTypes.StringValue(unlockKey.UnlockKey)
+	return diags
+}
+
+// isKnown reports whether a value is set in the configuration and known at apply time.
+func isKnown(value interface {
+	IsNull() bool
+	IsUnknown() bool
+}) bool {
+	return !value.IsNull() && !value.IsUnknown()
+}
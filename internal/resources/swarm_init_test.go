@@ -3,8 +3,11 @@ package resources
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,12 +42,41 @@ func TestSwarmInitResource_Schema(t *testing.T) {
 	assert.Contains(t, resp.Schema.Attributes, "listen_addr")
 	assert.Contains(t, resp.Schema.Attributes, "manager_token")
 	assert.Contains(t, resp.Schema.Attributes, "worker_token")
+	assert.Contains(t, resp.Schema.Attributes, "task_history_retention_limit")
+	assert.Contains(t, resp.Schema.Attributes, "snapshot_interval")
+	assert.Contains(t, resp.Schema.Attributes, "keep_old_snapshots")
+	assert.Contains(t, resp.Schema.Attributes, "heartbeat_period")
+	assert.Contains(t, resp.Schema.Attributes, "election_tick")
+	assert.Contains(t, resp.Schema.Attributes, "heartbeat_tick")
+	assert.Contains(t, resp.Schema.Attributes, "rotate_worker_token")
+	assert.Contains(t, resp.Schema.Attributes, "rotate_manager_token")
+	assert.Contains(t, resp.Schema.Attributes, "autolock")
+	assert.Contains(t, resp.Schema.Attributes, "unlock_key")
+	assert.Contains(t, resp.Schema.Attributes, "rotate_unlock_key")
+	assert.Contains(t, resp.Schema.Attributes, "allow_quorum_loss")
+	assert.Contains(t, resp.Schema.Attributes, "default_addr_pool")
+	assert.Contains(t, resp.Schema.Attributes, "subnet_size")
+	assert.Contains(t, resp.Schema.Attributes, "data_path_port")
+	assert.Contains(t, resp.Schema.Attributes, "data_path_addr")
+	assert.Contains(t, resp.Schema.Attributes, "force_new_cluster")
+	assert.Contains(t, resp.Schema.Attributes, "availability")
+	assert.Contains(t, resp.Schema.Attributes, "node_cert_expiry")
+	assert.Contains(t, resp.Schema.Attributes, "external_ca")
+	assert.Contains(t, resp.Schema.Attributes, "signing_ca_cert")
+	assert.Contains(t, resp.Schema.Attributes, "signing_ca_key")
+	assert.Contains(t, resp.Schema.Attributes, "ca_rotation")
+	assert.Contains(t, resp.Schema.Attributes, "adopt_existing")
+	assert.Contains(t, resp.Schema.Attributes, "cluster_id")
 	
 	// Verify sensitive attributes
 	managerToken := resp.Schema.Attributes["manager_token"]
 	workerToken := resp.Schema.Attributes["worker_token"]
+	unlockKey := resp.Schema.Attributes["unlock_key"]
 	assert.True(t, managerToken.(interface{ IsSensitive() bool }).IsSensitive())
 	assert.True(t, workerToken.(interface{ IsSensitive() bool }).IsSensitive())
+	assert.True(t, unlockKey.(interface{ IsSensitive() bool }).IsSensitive())
+	assert.True(t, resp.Schema.Attributes["signing_ca_cert"].(interface{ IsSensitive() bool }).IsSensitive())
+	assert.True(t, resp.Schema.Attributes["signing_ca_key"].(interface{ IsSensitive() bool }).IsSensitive())
 }
 
 func TestSwarmInitResource_Configure(t *testing.T) {
@@ -64,4 +96,117 @@ func TestSwarmInitResource_Configure(t *testing.T) {
 func TestSwarmInitResource_InterfaceCompliance(t *testing.T) {
 	var _ resource.Resource = &swarmInitResource{}
 	var _ resource.ResourceWithConfigure = &swarmInitResource{}
-}
\ No newline at end of file
+	var _ resource.ResourceWithImportState = &swarmInitResource{}
+	var _ resource.ResourceWithValidateConfig = &swarmInitResource{}
+}
+
+func TestExpandClusterSpec(t *testing.T) {
+	model := swarmInitResourceModel{
+		TaskHistoryRetentionLimit: tfTypes.Int64Value(10),
+		SnapshotInterval:          tfTypes.Int64Value(5000),
+		KeepOldSnapshots:          tfTypes.Int64Unknown(),
+		HeartbeatPeriod:           tfTypes.StringValue("10s"),
+		ElectionTick:              tfTypes.Int64Null(),
+		HeartbeatTick:             tfTypes.Int64Value(2),
+		Autolock:                  tfTypes.BoolValue(true),
+	}
+	keep := uint64(3)
+	spec := swarm.Spec{
+		Raft: swarm.RaftConfig{KeepOldSnapshots: &keep, ElectionTick: 10},
+	}
+
+	diags := expandClusterSpec(&model, &spec)
+
+	assert.False(t, diags.HasError())
+	assert.Equal(t, int64(10), *spec.Orchestration.TaskHistoryRetentionLimit)
+	assert.Equal(t, uint64(5000), spec.Raft.SnapshotInterval)
+	assert.Equal(t, uint64(3), *spec.Raft.KeepOldSnapshots) // unknown keeps the current value
+	assert.Equal(t, 10, spec.Raft.ElectionTick)             // null keeps the current value
+	assert.Equal(t, 2, spec.Raft.HeartbeatTick)
+	assert.Equal(t, 10*time.Second, spec.Dispatcher.HeartbeatPeriod)
+	assert.True(t, spec.EncryptionConfig.AutoLockManagers)
+
+	model.HeartbeatPeriod = tfTypes.StringValue("often")
+	diags = expandClusterSpec(&model, &spec)
+	assert.True(t, diags.HasError())
+}
+
+func TestFlattenClusterSpec(t *testing.T) {
+	limit := int64(5)
+	spec := swarm.Spec{
+		Orchestration: swarm.OrchestrationConfig{TaskHistoryRetentionLimit: &limit},
+		Raft:          swarm.RaftConfig{SnapshotInterval: 10000, ElectionTick: 10, HeartbeatTick: 1},
+		Dispatcher:    swarm.DispatcherConfig{HeartbeatPeriod: time.Minute},
+	}
+	model := swarmInitResourceModel{HeartbeatPeriod: tfTypes.StringValue("1m")}
+
+	flattenClusterSpec(spec, &model)
+
+	assert.Equal(t, int64(5), model.TaskHistoryRetentionLimit.ValueInt64())
+	assert.Equal(t, int64(10000), model.SnapshotInterval.ValueInt64())
+	assert.Equal(t, int64(0), model.KeepOldSnapshots.ValueInt64())
+	assert.Equal(t, int64(10), model.ElectionTick.ValueInt64())
+	assert.Equal(t, int64(1), model.HeartbeatTick.ValueInt64())
+	assert.Equal(t, "1m", model.HeartbeatPeriod.ValueString())
+	assert.False(t, model.Autolock.ValueBool())
+}
+
+func TestExpandInitNetwork(t *testing.T) {
+	pools, _ := tfTypes.ListValueFrom(context.Background(), tfTypes.StringType, []string{"172.30.0.0/16"})
+	model := swarmInitResourceModel{
+		DefaultAddrPool: pools,
+		SubnetSize:      tfTypes.Int64Value(26),
+		DataPathPort:    tfTypes.Int64Unknown(),
+		DataPathAddr:    tfTypes.StringValue("eth1"),
+		ForceNewCluster: tfTypes.BoolNull(),
+		Availability:    tfTypes.StringValue("drain"),
+	}
+	initRequest := swarm.InitRequest{}
+
+	diags := expandInitNetwork(context.Background(), &model, &initRequest)
+
+	assert.False(t, diags.HasError())
+	assert.Equal(t, []string{"172.30.0.0/16"}, initRequest.DefaultAddrPool)
+	assert.Equal(t, uint32(26), initRequest.SubnetSize)
+	assert.Equal(t, uint32(0), initRequest.DataPathPort) // unknown lets Docker choose
+	assert.Equal(t, "eth1", initRequest.DataPathAddr)
+	assert.False(t, initRequest.ForceNewCluster)
+	assert.Equal(t, swarm.NodeAvailabilityDrain, initRequest.Availability)
+}
+
+func TestFlattenInitNetwork(t *testing.T) {
+	info := swarm.ClusterInfo{
+		DefaultAddrPool: []string{"10.0.0.0/8"},
+		SubnetSize:      24,
+		DataPathPort:    4789,
+	}
+	model := swarmInitResourceModel{}
+
+	diags := flattenInitNetwork(context.Background(), info, &model)
+
+	assert.False(t, diags.HasError())
+	assert.Len(t, model.DefaultAddrPool.Elements(), 1)
+	assert.Equal(t, int64(24), model.SubnetSize.ValueInt64())
+	assert.Equal(t, int64(4789), model.DataPathPort.ValueInt64())
+}
+
+func TestCheckInitNetwork(t *testing.T) {
+	info := swarm.ClusterInfo{
+		DefaultAddrPool: []string{"10.0.0.0/8"},
+		SubnetSize:      24,
+		DataPathPort:    4789,
+	}
+	pools, _ := tfTypes.ListValueFrom(context.Background(), tfTypes.StringType, []string{"10.0.0.0/8"})
+	model := swarmInitResourceModel{
+		DefaultAddrPool: pools,
+		SubnetSize:      tfTypes.Int64Unknown(),
+		DataPathPort:    tfTypes.Int64Null(),
+	}
+
+	diags := checkInitNetwork(context.Background(), &model, info)
+	assert.False(t, diags.HasError())
+
+	model.SubnetSize = tfTypes.Int64Value(26)
+	diags = checkInitNetwork(context.Background(), &model, info)
+	assert.True(t, diags.HasError())
+}
@@ -2,16 +2,29 @@ package resources
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/sntns/terraform-provider-swarm/internal/docker"
@@ -19,8 +32,10 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &swarmJoinResource{}
-	_ resource.ResourceWithConfigure = &swarmJoinResource{}
+	_ resource.Resource                   = &swarmJoinResource{}
+	_ resource.ResourceWithConfigure      = &swarmJoinResource{}
+	_ resource.ResourceWithImportState    = &swarmJoinResource{}
+	_ resource.ResourceWithValidateConfig = &swarmJoinResource{}
 )
 
 // NewSwarmJoinResource is a helper function to simplify the provider implementation.
@@ -30,7 +45,8 @@ func NewSwarmJoinResource() resource.Resource {
 
 // swarmJoinResource is the resource implementation.
 type swarmJoinResource struct {
-	client *client.Client
+	client       *client.Client
+	providerData *SwarmProviderData
 }
 
 // swarmJoinResourceModel maps the resource schema data.
@@ -42,9 +58,35 @@ type swarmJoinResourceModel struct {
 	ListenAddr    tfTypes.String `tfsdk:"listen_addr"`
 	NodeID        tfTypes.String `tfsdk:"node_id"`
 	NodeRole      tfTypes.String `tfsdk:"node_role"`
+	Role          tfTypes.String `tfsdk:"role"`
 	Node          *docker.TfNode `tfsdk:"node"`
+	Manager       *docker.TfNode `tfsdk:"manager"`
+
+	DestroyStrategy tfTypes.String `tfsdk:"destroy_strategy"`
+	DrainTimeout    tfTypes.String `tfsdk:"drain_timeout"`
+	RemoveNode      tfTypes.Bool   `tfsdk:"remove_node"`
+	AllowQuorumLoss tfTypes.Bool   `tfsdk:"allow_quorum_loss"`
+
+	RejoinOnMismatch tfTypes.Bool `tfsdk:"rejoin_on_mismatch"`
+
+	JoinAttempts       tfTypes.Int64  `tfsdk:"join_attempts"`
+	JoinAttemptTimeout tfTypes.String `tfsdk:"join_attempt_timeout"`
+	JoinBackoff        tfTypes.String `tfsdk:"join_backoff"`
+	ReadyTimeout       tfTypes.String `tfsdk:"ready_timeout"`
 }
 
+const (
+	// defaultJoinAttempts is the number of rounds over the remote addresses.
+	defaultJoinAttempts = 5
+	// defaultJoinAttemptTimeout bounds a single join request.
+	defaultJoinAttemptTimeout = "1m"
+	// defaultJoinBackoff is the delay before the second round, doubled for
+	// every following round.
+	defaultJoinBackoff = "2s"
+	// maxJoinBackoff caps the delay between two rounds.
+	maxJoinBackoff = time.Minute
+)
+
 // Use the same struct as docker.TfNode for plan.Node
 
 // Metadata returns the resource type name.
@@ -57,7 +99,8 @@ func (r *swarmJoinResource) Schema(_ context.Context, _ resource.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Description: "Join a node to an existing Docker Swarm cluster.",
 		Attributes: map[string]schema.Attribute{
-			"node": docker.NodeSchema,
+			"node":    docker.NodeSchema,
+			"manager": docker.ManagerNodeSchema,
 			"id": schema.StringAttribute{
 				Description: "Resource identifier",
 				Computed:    true,
@@ -66,22 +109,32 @@ func (r *swarmJoinResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				},
 			},
 			"join_token": schema.StringAttribute{
-				Description: "Join token from the swarm manager",
-				Required:    true,
+				Description: "Join token from the swarm manager, read from the manager for the requested role when omitted",
+				Optional:    true,
 				Sensitive:   true,
 			},
 			"remote_addrs": schema.SetAttribute{
-				Description: "Addresses of existing swarm managers",
-				Required:    true,
+				Description: "Addresses of existing swarm managers, read from the manager when omitted",
+				Optional:    true,
+				Computed:    true,
 				ElementType: tfTypes.StringType,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"advertise_addr": schema.StringAttribute{
 				Description: "Externally reachable address advertised to other nodes",
 				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfAddrChanged(),
+				},
 			},
 			"listen_addr": schema.StringAttribute{
 				Description: "Listen address for the raft consensus protocol (managers only)",
 				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfAddrChanged(),
+				},
 			},
 			"node_id": schema.StringAttribute{
 				Description: "ID of the node after joining",
@@ -91,39 +144,106 @@ func (r *swarmJoinResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				Description: "Role of the node (manager or worker)",
 				Computed:    true,
 			},
+			"role": schema.StringAttribute{
+				Description: "Desired role of the node (manager or worker), changed in place through a manager",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(swarm.NodeRoleManager), string(swarm.NodeRoleWorker)),
+				},
+			},
+			"destroy_strategy": schema.StringAttribute{
+				Description: "How the node leaves the swarm on destroy: leave right away (leave) or drain its tasks first (drain)",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(destroyStrategyLeave),
+				Validators: []validator.String{
+					stringvalidator.OneOf(destroyStrategyLeave, destroyStrategyDrain),
+				},
+			},
+			"drain_timeout": schema.StringAttribute{
+				Description: "Maximum time to wait for the tasks of the node to stop when draining it, and for the node to be down before removing it",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultDrainTimeout),
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"remove_node": schema.BoolAttribute{
+				Description: "Remove the node from the node list of the managers after it left the swarm",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			"join_attempts": schema.Int64Attribute{
+				Description: "Number of rounds over remote_addrs before giving up on transient join errors",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(defaultJoinAttempts),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"join_attempt_timeout": schema.StringAttribute{
+				Description: "Maximum duration of a single join request",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultJoinAttemptTimeout),
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"join_backoff": schema.StringAttribute{
+				Description: "Delay before retrying a failed round, doubled after every round up to one minute",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultJoinBackoff),
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"ready_timeout": schema.StringAttribute{
+				Description: "Maximum time to wait after joining for the node to be ready and, for a manager, reachable",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(defaultReadyTimeout),
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+			"rejoin_on_mismatch": schema.BoolAttribute{
+				Description: "Leave the swarm the node is part of and join the configured one when they differ, instead of failing",
+				Optional:    true,
+			},
+			"allow_quorum_loss": schema.BoolAttribute{
+				Description: "Demote or remove the node even when the remaining managers lose the quorum or are left without a manager",
+				Optional:    true,
+			},
 		},
 	}
 }
 
-// Plus de configuration à faire côté provider
+// Configure keeps the provider default connection, used when node is omitted.
 func (r *swarmJoinResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, diags := ConfiguredProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	r.providerData = providerData
 }
 
 // Create creates the resource and sets the initial Terraform state.
 func (r *swarmJoinResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	var plan struct {
-		Node          docker.TfNode  `tfsdk:"node"`
-		JoinToken     tfTypes.String `tfsdk:"join_token"`
-		RemoteAddrs   tfTypes.Set    `tfsdk:"remote_addrs"`
-		AdvertiseAddr tfTypes.String `tfsdk:"advertise_addr"`
-		ListenAddr    tfTypes.String `tfsdk:"listen_addr"`
-		ID            tfTypes.String `tfsdk:"id"`
-		NodeID        tfTypes.String `tfsdk:"node_id"`
-		NodeRole      tfTypes.String `tfsdk:"node_role"`
-	}
+	var plan swarmJoinResourceModel
 	diags := req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Use shared extraction logic
-	dockerConfig := docker.ExtractConfig(plan.Node)
-	tflog.Debug(ctx, "Docker client config", map[string]interface{}{
-		"host":     dockerConfig.Host,
-		"ssh_opts": dockerConfig.SSHOpts,
-	})
-	dockerClient, err := dockerConfig.NewClient()
+	// Use the node block, or the provider connection when it is omitted
+	dockerClient, err := r.providerData.NewClient(plan.Node)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Unable to Create Docker Client",
@@ -133,6 +253,21 @@ func (r *swarmJoinResource) Create(ctx context.Context, req resource.CreateReque
 	}
 	r.client = dockerClient
 
+	// Read the join token and the manager addresses from the manager when
+	// they are not configured
+	joinToken := plan.JoinToken.ValueString()
+	if plan.JoinToken.IsNull() || !isKnown(plan.RemoteAddrs) {
+		var managerJoinToken string
+		managerJoinToken, diags = r.readJoinSettings(ctx, &plan)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if plan.JoinToken.IsNull() {
+			joinToken = managerJoinToken
+		}
+	}
+
 	// Get remote addresses
 	var remoteAddrs []string
 	diags = plan.RemoteAddrs.ElementsAs(ctx, &remoteAddrs, false)
@@ -143,7 +278,7 @@ func (r *swarmJoinResource) Create(ctx context.Context, req resource.CreateReque
 
 	// Prepare join request
 	joinRequest := swarm.JoinRequest{
-		JoinToken:   plan.JoinToken.ValueString(),
+		JoinToken:   joinToken,
 		RemoteAddrs: remoteAddrs,
 	}
 
@@ -155,20 +290,45 @@ func (r *swarmJoinResource) Create(ctx context.Context, req resource.CreateReque
 		joinRequest.ListenAddr = plan.ListenAddr.ValueString()
 	}
 
-	// Join the swarm using Docker API
-	err = r.client.SwarmJoin(ctx, joinRequest)
+	// A node already part of the target swarm, e.g. after a failed apply or
+	// a lost state, does not need to join again
+	nodeInfo, err := r.client.Info(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error joining swarm",
-			"Could not join swarm, unexpected error: "+err.Error(),
+			"Error getting node info",
+			"Could not get node info before joining swarm, unexpected error: "+err.Error(),
 		)
 		return
 	}
+	joined := false
+	if nodeInfo.Swarm.LocalNodeState != swarm.LocalNodeStateInactive {
+		sameSwarm, checkDiags := r.isTargetSwarm(ctx, nodeInfo.Swarm, plan.Manager, remoteAddrs)
+		resp.Diagnostics.Append(checkDiags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if sameSwarm {
+			joined = true
+			tflog.Debug(ctx, "node already part of the swarm", map[string]interface{}{
+				"node_id": nodeInfo.Swarm.NodeID,
+			})
+		} else {
+			resp.Diagnostics.Append(r.leaveOtherSwarm(ctx, nodeInfo.Swarm, &plan)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
 
-	tflog.Trace(ctx, "joined swarm")
+	if !joined {
+		resp.Diagnostics.Append(r.join(ctx, joinRequest, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	// Get node info to populate computed fields
-	nodeInfo, err := r.client.Info(ctx)
+	nodeInfo, err = r.client.Info(ctx)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting node info",
@@ -178,25 +338,67 @@ func (r *swarmJoinResource) Create(ctx context.Context, req resource.CreateReque
 	}
 
 	nodeID := nodeInfo.Swarm.NodeID
-	clusterID := nodeInfo.Swarm.Cluster.ID
 
-	// Determine node role from join token or node info
-	nodeRole := "worker"
-	if strings.Contains(plan.JoinToken.ValueString(), "SWMTKN-1-") {
-		// This is a simplified check - manager tokens typically have different patterns
-		parts := strings.Split(plan.JoinToken.ValueString(), "-")
-		if len(parts) >= 4 && len(parts[3]) > 30 {
-			nodeRole = "manager"
+	// Apply the desired role and read back the effective one
+	managerClient, err := r.managerClient(plan.Manager, nodeInfo.Swarm.ControlAvailable)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Manager Docker Client",
+			"An unexpected error occurred when creating the Docker client for the manager. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+	clusterID, err := swarmClusterID(ctx, nodeInfo.Swarm, managerClient)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error inspecting swarm",
+			"Could not read the ID of the swarm through the manager, unexpected error: "+err.Error(),
+		)
+		return
+	}
+	nodeRole, err := readNodeRole(ctx, managerClient, nodeID, nodeInfo.Swarm.ControlAvailable)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error inspecting node",
+			"Could not read the role of node "+nodeID+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+	if !plan.Role.IsNull() && plan.Role.ValueString() != string(nodeRole) {
+		nodeRole = swarm.NodeRole(plan.Role.ValueString())
+		resp.Diagnostics.Append(setNodeRole(ctx, managerClient, nodeID, nodeRole)...)
+		if resp.Diagnostics.HasError() {
+			return
 		}
 	}
 
+	// Resources depending on the node, such as services constrained to it,
+	// need it ready and, for a manager, reachable in the raft cluster
+	readyTimeout, err := time.ParseDuration(plan.ReadyTimeout.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid ready_timeout",
+			"Could not parse ready_timeout "+plan.ReadyTimeout.ValueString()+" as a duration: "+err.Error(),
+		)
+		return
+	}
+	readyClient := managerClient
+	if plan.Manager == nil && nodeRole != swarm.NodeRoleManager {
+		// A demoted node cannot inspect itself anymore
+		readyClient = nil
+	}
+	resp.Diagnostics.Append(waitForNodeReady(ctx, r.client, readyClient, nodeID, nodeRole, readyTimeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Map response body to schema and populate Computed attribute values
 	plan.ID = tfTypes.StringValue(fmt.Sprintf("%s-%s", nodeID, clusterID))
 	plan.NodeID = tfTypes.StringValue(nodeID)
-	plan.NodeRole = tfTypes.StringValue(nodeRole)
+	plan.NodeRole = tfTypes.StringValue(string(nodeRole))
 
 	// Set state to fully populated data
-	diags = resp.State.Set(ctx, plan)
+	diags = resp.State.Set(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -214,18 +416,15 @@ func (r *swarmJoinResource) Read(ctx context.Context, req resource.ReadRequest,
 
 	// Recreate Docker client from state.Node if needed
 	if r.client == nil {
-		if state.Node != nil {
-			dockerConfig := docker.ExtractConfig(*state.Node)
-			dockerClient, err := dockerConfig.NewClient()
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"Unable to Create Docker Client in Read",
-					"An unexpected error occurred when creating the Docker client in Read. \n\nDocker Client Error: "+err.Error(),
-				)
-				return
-			}
-			r.client = dockerClient
+		dockerClient, err := r.providerData.NewClient(state.Node)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create Docker Client in Read",
+				"An unexpected error occurred when creating the Docker client in Read. \n\nDocker Client Error: "+err.Error(),
+			)
+			return
 		}
+		r.client = dockerClient
 	}
 
 	// Check if node is still part of swarm
@@ -239,11 +438,19 @@ func (r *swarmJoinResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	nodeID := nodeInfo.Swarm.NodeID
-	if nodeID == "" {
+	if nodeID == "" || nodeInfo.Swarm.LocalNodeState == swarm.LocalNodeStateInactive {
 		// Node is no longer part of swarm, remove from state
 		resp.State.RemoveResource(ctx)
 		return
 	}
+	if nodeInfo.Swarm.LocalNodeState == swarm.LocalNodeStateError {
+		resp.State.RemoveResource(ctx)
+		resp.Diagnostics.AddWarning(
+			"Node in Error State",
+			"The node "+nodeID+" reports an error swarm state: "+nodeInfo.Swarm.Error+". The resource will be recreated.",
+		)
+		return
+	}
 	if !state.NodeID.IsNull() && state.NodeID.ValueString() != nodeID {
 		resp.State.RemoveResource(ctx)
 		resp.Diagnostics.AddWarning(
@@ -256,19 +463,87 @@ func (r *swarmJoinResource) Read(ctx context.Context, req resource.ReadRequest,
 	// Update state with current node info
 	state.NodeID = tfTypes.StringValue(nodeID)
 
-	// Try to get more detailed node info to determine role
-	nodeList, err := r.client.NodeList(ctx, types.NodeListOptions{})
-	if err == nil {
-		for _, node := range nodeList {
-			if node.ID == nodeID {
-				if node.Spec.Role == swarm.NodeRoleManager {
-					state.NodeRole = tfTypes.StringValue("manager")
-				} else {
-					state.NodeRole = tfTypes.StringValue("worker")
-				}
-				break
+	// Refresh the role so that promotions and demotions made outside of
+	// Terraform show up as drift
+	managerClient, err := r.managerClient(state.Manager, nodeInfo.Swarm.ControlAvailable)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Manager Docker Client in Read",
+			"An unexpected error occurred when creating the Docker client for the manager in Read. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	// A node that joined another swarm, or was re-initialized, shows up as
+	// a replacement
+	clusterID, err := swarmClusterID(ctx, nodeInfo.Swarm, managerClient)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error inspecting swarm",
+			"Could not read the ID of the swarm of node "+nodeID+": "+err.Error(),
+		)
+		return
+	}
+	stateClusterID := strings.TrimPrefix(state.ID.ValueString(), nodeID+"-")
+	sameSwarm := clusterID == "" || stateClusterID == "" || clusterID == stateClusterID
+	if sameSwarm && clusterID == "" && state.Manager == nil {
+		// Without a manager, a worker only knows the managers it talks to
+		var remoteAddrs []string
+		resp.Diagnostics.Append(state.RemoteAddrs.ElementsAs(ctx, &remoteAddrs, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		sameSwarm = len(remoteAddrs) == 0 || knowsRemoteManager(nodeInfo.Swarm, remoteAddrs)
+	}
+	if !sameSwarm {
+		resp.State.RemoveResource(ctx)
+		resp.Diagnostics.AddWarning(
+			"Node Moved to Another Swarm",
+			"The node "+nodeID+" is no longer part of the swarm it joined. The resource will be recreated.",
+		)
+		return
+	}
+	if clusterID != "" {
+		state.ID = tfTypes.StringValue(fmt.Sprintf("%s-%s", nodeID, clusterID))
+	}
+
+	var raftAddr string
+	nodeRole := swarm.NodeRoleWorker
+	if nodeInfo.Swarm.ControlAvailable {
+		nodeRole = swarm.NodeRoleManager
+	}
+	if managerClient != nil {
+		node, _, err := managerClient.NodeInspectWithRaw(ctx, nodeID)
+		if err != nil {
+			if client.IsErrNotFound(err) {
+				resp.State.RemoveResource(ctx)
+				resp.Diagnostics.AddWarning(
+					"Node Moved to Another Swarm",
+					"The node "+nodeID+" is not known by the managers of the swarm anymore. The resource will be recreated.",
+				)
+				return
 			}
+			resp.Diagnostics.AddError(
+				"Error inspecting node",
+				"Could not read the role of node "+nodeID+": "+err.Error(),
+			)
+			return
 		}
+		nodeRole = node.Spec.Role
+		if node.ManagerStatus != nil {
+			raftAddr = node.ManagerStatus.Addr
+		}
+	}
+	state.NodeRole = tfTypes.StringValue(string(nodeRole))
+
+	// A node now advertising another address shows up as a replacement
+	if raftAddr == "" {
+		raftAddr = nodeInfo.Swarm.NodeAddr
+	}
+	state.ListenAddr = refreshListenAddr(state.ListenAddr, state.AdvertiseAddr, raftAddr)
+	state.AdvertiseAddr = refreshAddr(state.AdvertiseAddr, raftAddr)
+	if !state.Role.IsNull() {
+		state.Role = state.NodeRole
 	}
 
 	// Set refreshed state
@@ -281,11 +556,72 @@ func (r *swarmJoinResource) Read(ctx context.Context, req resource.ReadRequest,
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *swarmJoinResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Most swarm join settings cannot be updated after joining
-	resp.Diagnostics.AddError(
-		"Swarm Join Update Not Supported",
-		"Swarm join settings cannot be updated after joining. Consider recreating the resource with new settings.",
-	)
+	var plan, state swarmJoinResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Promote or demote the node in place
+	if !plan.Role.IsNull() && !plan.Role.Equal(state.NodeRole) {
+		if r.client == nil {
+			dockerClient, err := r.providerData.NewClient(plan.Node)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Unable to Create Docker Client in Update",
+					"An unexpected error occurred when creating the Docker client in Update. \n\nDocker Client Error: "+err.Error(),
+				)
+				return
+			}
+			r.client = dockerClient
+		}
+		managerClient, err := r.managerClient(plan.Manager, state.NodeRole.ValueString() == string(swarm.NodeRoleManager))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create Manager Docker Client in Update",
+				"An unexpected error occurred when creating the Docker client for the manager in Update. \n\nDocker Client Error: "+err.Error(),
+			)
+			return
+		}
+		nodeID := state.NodeID.ValueString()
+		if plan.Role.ValueString() == string(swarm.NodeRoleWorker) && managerClient != nil && !plan.AllowQuorumLoss.ValueBool() {
+			resp.Diagnostics.Append(checkManagerRemoval(ctx, managerClient, nodeID, true)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+		resp.Diagnostics.Append(setNodeRole(ctx, managerClient, nodeID, swarm.NodeRole(plan.Role.ValueString()))...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		state.NodeRole = plan.Role
+	}
+	state.Role = plan.Role
+	state.Manager = plan.Manager
+	state.DestroyStrategy = plan.DestroyStrategy
+	state.DrainTimeout = plan.DrainTimeout
+	state.RemoveNode = plan.RemoveNode
+	state.AllowQuorumLoss = plan.AllowQuorumLoss
+	state.RejoinOnMismatch = plan.RejoinOnMismatch
+	state.JoinAttempts = plan.JoinAttempts
+	state.JoinAttemptTimeout = plan.JoinAttemptTimeout
+	state.JoinBackoff = plan.JoinBackoff
+	state.ReadyTimeout = plan.ReadyTimeout
+
+	// The join token and remote addresses are only used when joining, a
+	// rotated token or a new manager list does not affect a joined node.
+	// Address changes force a replacement through their plan modifiers.
+	state.JoinToken = plan.JoinToken
+	state.RemoteAddrs = plan.RemoteAddrs
+	state.AdvertiseAddr = plan.AdvertiseAddr
+	state.ListenAddr = plan.ListenAddr
+	state.Node = plan.Node
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
@@ -299,22 +635,84 @@ func (r *swarmJoinResource) Delete(ctx context.Context, req resource.DeleteReque
 
 	// Recreate Docker client from state.Node if needed
 	if r.client == nil {
-		if state.Node != nil {
-			dockerConfig := docker.ExtractConfig(*state.Node)
-			dockerClient, err := dockerConfig.NewClient()
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"Unable to Create Docker Client in Delete",
-					"An unexpected error occurred when creating the Docker client in Delete. \n\nDocker Client Error: "+err.Error(),
-				)
+		dockerClient, err := r.providerData.NewClient(state.Node)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create Docker Client in Delete",
+				"An unexpected error occurred when creating the Docker client in Delete. \n\nDocker Client Error: "+err.Error(),
+			)
+			return
+		}
+		r.client = dockerClient
+	}
+
+	// Draining and removing the node both go through a manager
+	nodeID := state.NodeID.ValueString()
+	drain := state.DestroyStrategy.ValueString() == destroyStrategyDrain
+	remove := state.RemoveNode.ValueBool()
+	var managerClient *client.Client
+	var timeout time.Duration
+	var err error
+	if drain || remove {
+		timeout, err = time.ParseDuration(state.DrainTimeout.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("drain_timeout"),
+				"Invalid Drain Timeout",
+				"Could not parse drain_timeout "+state.DrainTimeout.ValueString()+" as a duration: "+err.Error(),
+			)
+			return
+		}
+
+		isManager := state.NodeRole.ValueString() == string(swarm.NodeRoleManager)
+		managerClient, err = r.managerClient(state.Manager, isManager)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to Create Manager Docker Client in Delete",
+				"An unexpected error occurred when creating the Docker client for the manager in Delete. \n\nDocker Client Error: "+err.Error(),
+			)
+			return
+		}
+		if managerClient == nil || (remove && state.Manager == nil) {
+			resp.Diagnostics.AddError(
+				"Manager Connection Required",
+				"Draining node "+nodeID+" or removing it from the swarm requires a manager connection. "+
+					"Set the manager block to another manager of the swarm.",
+			)
+			return
+		}
+	}
+
+	if drain {
+		resp.Diagnostics.Append(drainNode(ctx, managerClient, nodeID, timeout)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// A manager is demoted first so that it leaves gracefully and the raft
+	// membership shrinks, otherwise it leaves with force and stays a member.
+	// The node is a manager itself and can run its own demotion.
+	if state.NodeRole.ValueString() == string(swarm.NodeRoleManager) {
+		roleClient := managerClient
+		if roleClient == nil {
+			roleClient = r.client
+		}
+		if !state.AllowQuorumLoss.ValueBool() {
+			resp.Diagnostics.Append(checkManagerRemoval(ctx, roleClient, nodeID, true)...)
+			if resp.Diagnostics.HasError() {
 				return
 			}
-			r.client = dockerClient
 		}
+		resp.Diagnostics.Append(setNodeRole(ctx, roleClient, nodeID, swarm.NodeRoleWorker)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		waitForDemotion(ctx, r.client, nodeID, demotionTimeout)
 	}
 
 	// Leave the swarm using Docker API
-	err := r.client.SwarmLeave(ctx, false) // try regular leave first
+	err = r.client.SwarmLeave(ctx, false) // try regular leave first
 	if err != nil {
 		// Try force leave if regular leave fails
 		err = r.client.SwarmLeave(ctx, true)
@@ -328,4 +726,516 @@ func (r *swarmJoinResource) Delete(ctx context.Context, req resource.DeleteReque
 	}
 
 	tflog.Trace(ctx, "left swarm")
+
+	if remove {
+		resp.Diagnostics.Append(removeNode(ctx, managerClient, nodeID, timeout)...)
+	}
+}
+
+// ImportState adopts a node already part of a swarm from the connection described by the import ID.
+func (r *swarmJoinResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	node, err := parseNodeImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			err.Error(),
+		)
+		return
+	}
+
+	dockerClient, err := r.providerData.NewClient(&node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client in Import",
+			"An unexpected error occurred when creating the Docker client in Import. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	nodeInfo, err := dockerClient.Info(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Node Info",
+			"Could not read node info: "+err.Error(),
+		)
+		return
+	}
+	if nodeInfo.Swarm.NodeID == "" || nodeInfo.Swarm.LocalNodeState != swarm.LocalNodeStateActive {
+		resp.Diagnostics.AddError(
+			"Node Is Not Part of a Swarm",
+			"The node at "+node.ID()+" is not an active swarm member (state: "+string(nodeInfo.Swarm.LocalNodeState)+").",
+		)
+		return
+	}
+
+	remoteAddrs := make([]string, 0, len(nodeInfo.Swarm.RemoteManagers))
+	for _, manager := range nodeInfo.Swarm.RemoteManagers {
+		if manager.NodeID != nodeInfo.Swarm.NodeID {
+			remoteAddrs = append(remoteAddrs, manager.Addr)
+		}
+	}
+	remoteAddrsValue, diags := tfTypes.SetValueFrom(ctx, tfTypes.StringType, remoteAddrs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	nodeRole := "worker"
+	if nodeInfo.Swarm.ControlAvailable {
+		nodeRole = "manager"
+	}
+
+	clusterID := ""
+	if nodeInfo.Swarm.Cluster != nil {
+		clusterID = nodeInfo.Swarm.Cluster.ID
+	}
+
+	state := swarmJoinResourceModel{
+		ID:            tfTypes.StringValue(fmt.Sprintf("%s-%s", nodeInfo.Swarm.NodeID, clusterID)),
+		JoinToken:     tfTypes.StringNull(),
+		RemoteAddrs:   remoteAddrsValue,
+		AdvertiseAddr: tfTypes.StringNull(),
+		ListenAddr:    tfTypes.StringNull(),
+		NodeID:        tfTypes.StringValue(nodeInfo.Swarm.NodeID),
+		NodeRole:      tfTypes.StringValue(nodeRole),
+		Role:          tfTypes.StringNull(),
+		Node:          &node,
+
+		DestroyStrategy: tfTypes.StringValue(destroyStrategyLeave),
+		DrainTimeout:    tfTypes.StringValue(defaultDrainTimeout),
+		RemoveNode:      tfTypes.BoolValue(false),
+		AllowQuorumLoss: tfTypes.BoolNull(),
+
+		RejoinOnMismatch: tfTypes.BoolNull(),
+
+		JoinAttempts:       tfTypes.Int64Value(defaultJoinAttempts),
+		JoinAttemptTimeout: tfTypes.StringValue(defaultJoinAttemptTimeout),
+		JoinBackoff:        tfTypes.StringValue(defaultJoinBackoff),
+		ReadyTimeout:       tfTypes.StringValue(defaultReadyTimeout),
+	}
+
+	tflog.Trace(ctx, "imported swarm node", map[string]interface{}{
+		"node_id": nodeInfo.Swarm.NodeID,
+	})
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// join joins the swarm, trying each remote address in turn. Transient
+// errors are retried with an exponential backoff between rounds.
+func (r *swarmJoinResource) join(ctx context.Context, joinRequest swarm.JoinRequest, plan *swarmJoinResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	attemptTimeout, err := time.ParseDuration(plan.JoinAttemptTimeout.ValueString())
+	if err != nil {
+		diags.AddError(
+			"Invalid join_attempt_timeout",
+			"Could not parse join_attempt_timeout "+plan.JoinAttemptTimeout.ValueString()+" as a duration: "+err.Error(),
+		)
+		return diags
+	}
+	backoff, err := time.ParseDuration(plan.JoinBackoff.ValueString())
+	if err != nil {
+		diags.AddError(
+			"Invalid join_backoff",
+			"Could not parse join_backoff "+plan.JoinBackoff.ValueString()+" as a duration: "+err.Error(),
+		)
+		return diags
+	}
+
+	return joinSwarm(ctx, r.client, joinRequest, int(plan.JoinAttempts.ValueInt64()), attemptTimeout, backoff)
+}
+
+// joinSwarm joins the node to the swarm, trying the remote addresses in turn
+// for up to attempts rounds with an exponential backoff between rounds.
+func joinSwarm(ctx context.Context, nodeClient *client.Client, joinRequest swarm.JoinRequest, attempts int, attemptTimeout, backoff time.Duration) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		for _, remoteAddr := range joinRequest.RemoteAddrs {
+			request := joinRequest
+			request.RemoteAddrs = []string{remoteAddr}
+
+			attemptCtx, cancel := context.WithTimeout(ctx, attemptTimeout)
+			err := nodeClient.SwarmJoin(attemptCtx, request)
+			cancel()
+			if err == nil {
+				tflog.Trace(ctx, "joined swarm", map[string]interface{}{
+					"remote_addr": remoteAddr,
+					"attempt":     attempt,
+				})
+				return diags
+			}
+
+			// A request that timed out on our side may still have succeeded,
+			// or still be in progress
+			pending := false
+			if nodeInfo, infoErr := nodeClient.Info(ctx); infoErr == nil {
+				if nodeInfo.Swarm.LocalNodeState == swarm.LocalNodeStateActive {
+					tflog.Trace(ctx, "joined swarm", map[string]interface{}{
+						"remote_addr": remoteAddr,
+						"attempt":     attempt,
+					})
+					return diags
+				}
+				pending = nodeInfo.Swarm.LocalNodeState == swarm.LocalNodeStatePending
+			}
+
+			if !pending && !isRetryableJoinError(err) {
+				diags.AddError(
+					"Error joining swarm",
+					"Could not join swarm through "+remoteAddr+", unexpected error: "+err.Error(),
+				)
+				return diags
+			}
+			lastErr = err
+			tflog.Debug(ctx, "transient error joining swarm", map[string]interface{}{
+				"remote_addr": remoteAddr,
+				"attempt":     attempt,
+				"error":       err.Error(),
+			})
+		}
+
+		if attempt == attempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			diags.AddError(
+				"Joining Swarm Interrupted",
+				"Stopped retrying to join the swarm: "+ctx.Err().Error(),
+			)
+			return diags
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxJoinBackoff {
+			backoff = maxJoinBackoff
+		}
+	}
+
+	diags.AddError(
+		"Error joining swarm",
+		fmt.Sprintf("Could not join swarm after %d attempt(s) over %s, last error: %s",
+			attempts, strings.Join(joinRequest.RemoteAddrs, ", "), lastErr),
+	)
+	return diags
+}
+
+// retryableJoinErrors are fragments of the errors returned while the managers
+// are starting, electing a leader or unreachable for a moment.
+var retryableJoinErrors = []string{
+	"connection refused",
+	"connection reset",
+	"no route to host",
+	"timeout",
+	"deadline exceeded",
+	"code = unavailable",
+	"code = deadlineexceeded",
+	"code = canceled",
+	"the raft cluster is not ready",
+	"no leader",
+}
+
+// isRetryableJoinError reports whether a join error is transient. Invalid
+// tokens, a node already part of a swarm and any unknown error are fatal.
+func isRetryableJoinError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	for _, fragment := range retryableJoinErrors {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTargetSwarm reports whether a node already part of a swarm is part of
+// the swarm reached through the manager connection, or else through the
+// remote addresses.
+func (r *swarmJoinResource) isTargetSwarm(ctx context.Context, info swarm.Info, manager *docker.TfNode, remoteAddrs []string) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if info.LocalNodeState == swarm.LocalNodeStateLocked {
+		diags.AddError(
+			"Swarm Manager Locked",
+			"The node is a locked manager of a swarm and must be unlocked (see swarm_unlock) before it can be managed.",
+		)
+		return false, diags
+	}
+
+	if manager != nil {
+		managerClient, err := r.providerData.NewClient(manager)
+		if err != nil {
+			diags.AddError(
+				"Unable to Create Manager Docker Client",
+				"An unexpected error occurred when creating the Docker client for the manager. \n\nDocker Client Error: "+err.Error(),
+			)
+			return false, diags
+		}
+		_, _, err = managerClient.NodeInspectWithRaw(ctx, info.NodeID)
+		if err == nil {
+			return true, diags
+		}
+		if !client.IsErrNotFound(err) {
+			diags.AddError(
+				"Error inspecting node",
+				"Could not look node "+info.NodeID+" up on the manager, unexpected error: "+err.Error(),
+			)
+		}
+		return false, diags
+	}
+
+	return knowsRemoteManager(info, remoteAddrs), diags
+}
+
+// knowsRemoteManager reports whether one of the remote addresses is a
+// manager of the swarm the node is part of.
+func knowsRemoteManager(info swarm.Info, remoteAddrs []string) bool {
+	managers := map[string]bool{}
+	for _, peer := range info.RemoteManagers {
+		managers[normalizeManagerAddr(peer.Addr)] = true
+	}
+	if info.ControlAvailable && info.NodeAddr != "" {
+		managers[normalizeManagerAddr(info.NodeAddr)] = true
+	}
+	for _, addr := range remoteAddrs {
+		if managers[normalizeManagerAddr(addr)] {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeManagerAddr adds the default swarm port to a manager address without one.
+func normalizeManagerAddr(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return net.JoinHostPort(addr, "2377")
+	}
+	return addr
+}
+
+// leaveOtherSwarm makes the node leave the swarm it is part of when
+// rejoin_on_mismatch is set, and fails otherwise.
+func (r *swarmJoinResource) leaveOtherSwarm(ctx context.Context, info swarm.Info, plan *swarmJoinResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if !plan.RejoinOnMismatch.ValueBool() {
+		diags.AddError(
+			"Node Part of Another Swarm",
+			"The node "+info.NodeID+" is already part of a swarm that is not the one reached through remote_addrs. "+
+				"Make it leave that swarm, or set rejoin_on_mismatch to true.",
+		)
+		return diags
+	}
+
+	// Leaving with force keeps the manager as a member of the other swarm
+	if info.ControlAvailable && !plan.AllowQuorumLoss.ValueBool() {
+		diags.Append(checkManagerRemoval(ctx, r.client, info.NodeID, false)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+	err := r.client.SwarmLeave(ctx, info.ControlAvailable)
+	if err != nil {
+		diags.AddError(
+			"Error Leaving Swarm",
+			"Could not leave the swarm the node is part of, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+
+	tflog.Trace(ctx, "left other swarm", map[string]interface{}{
+		"node_id": info.NodeID,
+	})
+	return diags
+}
+
+// ValidateConfig checks that the join settings are either configured or can
+// be read from a manager.
+func (r *swarmJoinResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config swarmJoinResourceModel
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if config.Manager != nil {
+		return
+	}
+
+	if config.JoinToken.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("join_token"),
+			"Missing Join Token",
+			"Set join_token, or the manager block to read the join token of the requested role from a manager of the swarm.",
+		)
+	}
+	if config.RemoteAddrs.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("remote_addrs"),
+			"Missing Remote Addresses",
+			"Set remote_addrs, or the manager block to read the addresses of the managers of the swarm.",
+		)
+	}
+}
+
+// readJoinSettings connects to the manager and returns the join token of the
+// requested role. The remote addresses are set in the plan from the managers
+// of the swarm when they are not configured.
+func (r *swarmJoinResource) readJoinSettings(ctx context.Context, plan *swarmJoinResourceModel) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if plan.Manager == nil {
+		diags.AddError(
+			"Manager Connection Required",
+			"Reading the join token or the manager addresses requires a manager connection. "+
+				"Set the manager block to a manager of the swarm.",
+		)
+		return "", diags
+	}
+	managerClient, err := r.managerClient(plan.Manager, false)
+	if err != nil {
+		diags.AddError(
+			"Unable to Create Manager Docker Client",
+			"An unexpected error occurred when creating the Docker client of the manager. \n\nDocker Client Error: "+err.Error(),
+		)
+		return "", diags
+	}
+
+	swarmInfo, err := managerClient.SwarmInspect(ctx)
+	if err != nil {
+		diags.AddError(
+			"Error inspecting swarm",
+			"Could not inspect swarm through the manager to read the join token, unexpected error: "+err.Error(),
+		)
+		return "", diags
+	}
+	joinToken := swarmInfo.JoinTokens.Worker
+	if plan.Role.ValueString() == string(swarm.NodeRoleManager) {
+		joinToken = swarmInfo.JoinTokens.Manager
+	}
+
+	if !isKnown(plan.RemoteAddrs) {
+		args := filters.NewArgs(filters.Arg("role", string(swarm.NodeRoleManager)))
+		nodes, err := managerClient.NodeList(ctx, types.NodeListOptions{Filters: args})
+		if err != nil {
+			diags.AddError(
+				"Error listing nodes",
+				"Could not list the managers of the swarm, unexpected error: "+err.Error(),
+			)
+			return "", diags
+		}
+		addrs := managerAddrs(nodes)
+		if len(addrs) == 0 {
+			diags.AddError(
+				"No Reachable Manager",
+				"None of the managers of swarm "+swarmInfo.ID+" is reachable, the node cannot join it.",
+			)
+			return "", diags
+		}
+		var addrDiags diag.Diagnostics
+		plan.RemoteAddrs, addrDiags = tfTypes.SetValueFrom(ctx, tfTypes.StringType, addrs)
+		diags.Append(addrDiags...)
+	}
+
+	tflog.Debug(ctx, "read join settings from manager", map[string]interface{}{
+		"cluster_id":   swarmInfo.ID,
+		"remote_addrs": plan.RemoteAddrs.String(),
+	})
+	return joinToken, diags
+}
+
+// managerAddrs returns the raft addresses of the reachable managers, the
+// leader first.
+func managerAddrs(nodes []swarm.Node) []string {
+	var addrs []string
+	for _, node := range nodes {
+		if node.ManagerStatus == nil || node.ManagerStatus.Addr == "" ||
+			node.ManagerStatus.Reachability != swarm.ReachabilityReachable {
+			continue
+		}
+		if node.ManagerStatus.Leader {
+			addrs = append([]string{node.ManagerStatus.Addr}, addrs...)
+			continue
+		}
+		addrs = append(addrs, node.ManagerStatus.Addr)
+	}
+	return addrs
+}
+
+// managerClient returns a client connected to a manager of the swarm: the
+// configured manager if any, or the node itself when it is a manager.
+// It returns nil when no manager connection is available.
+func (r *swarmJoinResource) managerClient(manager *docker.TfNode, isManager bool) (*client.Client, error) {
+	if manager != nil {
+		return r.providerData.NewClient(manager)
+	}
+	if isManager {
+		return r.client, nil
+	}
+	return nil, nil
+}
+
+// readNodeRole returns the role of the node as seen by the swarm managers.
+// Without a manager connection, the node can only be a worker.
+func readNodeRole(ctx context.Context, managerClient *client.Client, nodeID string, isManager bool) (swarm.NodeRole, error) {
+	if managerClient == nil {
+		if isManager {
+			return swarm.NodeRoleManager, nil
+		}
+		return swarm.NodeRoleWorker, nil
+	}
+	node, _, err := managerClient.NodeInspectWithRaw(ctx, nodeID)
+	if err != nil {
+		return "", err
+	}
+	return node.Spec.Role, nil
+}
+
+// setNodeRole promotes or demotes the node through a manager connection.
+func setNodeRole(ctx context.Context, managerClient *client.Client, nodeID string, role swarm.NodeRole) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if managerClient == nil {
+		diags.AddError(
+			"Manager Connection Required",
+			"Changing the role of node "+nodeID+" to "+string(role)+" requires a manager connection. "+
+				"Set the manager block to a manager of the swarm.",
+		)
+		return diags
+	}
+
+	node, _, err := managerClient.NodeInspectWithRaw(ctx, nodeID)
+	if err != nil {
+		diags.AddError(
+			"Error inspecting node",
+			"Could not inspect node "+nodeID+", unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	if node.Spec.Role == role {
+		return diags
+	}
+
+	spec := node.Spec
+	spec.Role = role
+	err = managerClient.NodeUpdate(ctx, nodeID, node.Version, spec)
+	if err != nil {
+		diags.AddError(
+			"Error updating node role",
+			"Could not change the role of node "+nodeID+" to "+string(role)+", unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+
+	tflog.Trace(ctx, "changed node role", map[string]interface{}{
+		"node_id": nodeID,
+		"role":    string(role),
+	})
+	return diags
 }
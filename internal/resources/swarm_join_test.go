@@ -2,8 +2,11 @@ package resources
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/stretchr/testify/assert"
 )
@@ -41,6 +44,17 @@ func TestSwarmJoinResource_Schema(t *testing.T) {
 	assert.Contains(t, resp.Schema.Attributes, "listen_addr")
 	assert.Contains(t, resp.Schema.Attributes, "node_id")
 	assert.Contains(t, resp.Schema.Attributes, "node_role")
+	assert.Contains(t, resp.Schema.Attributes, "role")
+	assert.Contains(t, resp.Schema.Attributes, "manager")
+	assert.Contains(t, resp.Schema.Attributes, "destroy_strategy")
+	assert.Contains(t, resp.Schema.Attributes, "drain_timeout")
+	assert.Contains(t, resp.Schema.Attributes, "remove_node")
+	assert.Contains(t, resp.Schema.Attributes, "allow_quorum_loss")
+	assert.Contains(t, resp.Schema.Attributes, "rejoin_on_mismatch")
+	assert.Contains(t, resp.Schema.Attributes, "join_attempts")
+	assert.Contains(t, resp.Schema.Attributes, "join_attempt_timeout")
+	assert.Contains(t, resp.Schema.Attributes, "join_backoff")
+	assert.Contains(t, resp.Schema.Attributes, "ready_timeout")
 	
 	// Verify sensitive attributes
 	joinToken := resp.Schema.Attributes["join_token"]
@@ -62,4 +76,55 @@ func TestSwarmJoinResource_Configure(t *testing.T) {
 func TestSwarmJoinResource_InterfaceCompliance(t *testing.T) {
 	var _ resource.Resource = &swarmJoinResource{}
 	var _ resource.ResourceWithConfigure = &swarmJoinResource{}
-}
\ No newline at end of file
+	var _ resource.ResourceWithImportState = &swarmJoinResource{}
+	var _ resource.ResourceWithValidateConfig = &swarmJoinResource{}
+}
+
+func TestKnowsRemoteManager(t *testing.T) {
+	worker := swarm.Info{
+		NodeID:         "w1",
+		NodeAddr:       "192.168.1.101",
+		RemoteManagers: []swarm.Peer{{NodeID: "m1", Addr: "192.168.1.100:2377"}},
+	}
+
+	assert.True(t, knowsRemoteManager(worker, []string{"192.168.1.100:2377"}))
+	assert.True(t, knowsRemoteManager(worker, []string{"192.168.1.100"}))
+	assert.False(t, knowsRemoteManager(worker, []string{"10.0.0.5:2377"}))
+
+	// A manager also matches its own address
+	manager := swarm.Info{NodeID: "m2", NodeAddr: "192.168.1.102", ControlAvailable: true}
+	assert.True(t, knowsRemoteManager(manager, []string{"192.168.1.102:2377"}))
+}
+
+func TestIsRetryableJoinError(t *testing.T) {
+	retryable := []error{
+		errors.New("error during connect: dial tcp 192.168.1.100:2377: connect: connection refused"),
+		errors.New("rpc error: code = Unavailable desc = all SubConns are in TransientFailure"),
+		errors.New("Timeout was reached before node joined. The attempt to join the swarm will continue in the background."),
+		fmt.Errorf("join: %w", context.DeadlineExceeded),
+	}
+	for _, err := range retryable {
+		assert.True(t, isRetryableJoinError(err), err.Error())
+	}
+
+	fatal := []error{
+		errors.New("rpc error: code = InvalidArgument desc = A valid join token is necessary to join this cluster"),
+		errors.New("This node is already part of a swarm. Use \"docker swarm leave\" to leave this swarm and join another one."),
+		errors.New("invalid join token"),
+	}
+	for _, err := range fatal {
+		assert.False(t, isRetryableJoinError(err), err.Error())
+	}
+}
+
+func TestManagerAddrs(t *testing.T) {
+	nodes := []swarm.Node{
+		{ID: "m1", ManagerStatus: &swarm.ManagerStatus{Addr: "192.168.1.100:2377", Reachability: swarm.ReachabilityReachable}},
+		{ID: "m2", ManagerStatus: &swarm.ManagerStatus{Addr: "192.168.1.102:2377", Reachability: swarm.ReachabilityReachable, Leader: true}},
+		{ID: "m3", ManagerStatus: &swarm.ManagerStatus{Addr: "192.168.1.103:2377", Reachability: swarm.ReachabilityUnreachable}},
+		{ID: "w1"},
+	}
+
+	assert.Equal(t, []string{"192.168.1.102:2377", "192.168.1.100:2377"}, managerAddrs(nodes))
+	assert.Empty(t, managerAddrs(nodes[2:]))
+}
@@ -0,0 +1,384 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sntns/terraform-provider-swarm/internal/docker"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &swarmNodeResource{}
+	_ resource.ResourceWithConfigure = &swarmNodeResource{}
+)
+
+// NewSwarmNodeResource is a helper function to simplify the provider implementation.
+func NewSwarmNodeResource() resource.Resource {
+	return &swarmNodeResource{}
+}
+
+// swarmNodeResource is the resource implementation.
+type swarmNodeResource struct {
+	providerData *SwarmProviderData
+}
+
+// swarmNodeResourceModel maps the resource schema data.
+type swarmNodeResourceModel struct {
+	ID           tfTypes.String `tfsdk:"id"`
+	NodeID       tfTypes.String `tfsdk:"node_id"`
+	Hostname     tfTypes.String `tfsdk:"hostname"`
+	Name         tfTypes.String `tfsdk:"name"`
+	Labels       tfTypes.Map    `tfsdk:"labels"`
+	Availability tfTypes.String `tfsdk:"availability"`
+	Role         tfTypes.String `tfsdk:"role"`
+	Node         *docker.TfNode `tfsdk:"node"`
+
+	AllowQuorumLoss tfTypes.Bool `tfsdk:"allow_quorum_loss"`
+}
+
+// Metadata returns the resource type name.
+func (r *swarmNodeResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node"
+}
+
+// Schema defines the schema for the resource.
+func (r *swarmNodeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manage the labels, availability and role of an existing Docker Swarm node.",
+		Attributes: map[string]schema.Attribute{
+			"node": schema.SingleNestedAttribute{
+				Description: "Docker connection configuration for a manager node of the swarm. Defaults to the connection of the provider.",
+				Optional:    true,
+				Attributes:  docker.NodeSchema.Attributes,
+			},
+			"id": schema.StringAttribute{
+				Description: "ID of the managed node",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"node_id": schema.StringAttribute{
+				Description: "ID or hostname of the node to manage",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"hostname": schema.StringAttribute{
+				Description: "Hostname of the node",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the node in the swarm",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"labels": schema.MapAttribute{
+				Description: "Labels of the node, usable in placement constraints as node.labels.<key>",
+				ElementType: tfTypes.StringType,
+				Optional:    true,
+			},
+			"availability": schema.StringAttribute{
+				Description: "Availability of the node (active, pause or drain)",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(swarm.NodeAvailabilityActive),
+						string(swarm.NodeAvailabilityPause),
+						string(swarm.NodeAvailabilityDrain),
+					),
+				},
+			},
+			"role": schema.StringAttribute{
+				Description: "Role of the node (manager or worker)",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(string(swarm.NodeRoleManager), string(swarm.NodeRoleWorker)),
+				},
+			},
+			"allow_quorum_loss": schema.BoolAttribute{
+				Description: "Demote the node even when the remaining managers lose the quorum or are left without a manager",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// Configure keeps the provider default connection, used when node is omitted.
+func (r *swarmNodeResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, diags := ConfiguredProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	r.providerData = providerData
+}
+
+// Create applies the node spec and sets the initial Terraform state.
+func (r *swarmNodeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan swarmNodeResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dockerClient, err := r.providerData.NewClient(plan.Node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client",
+			"An unexpected error occurred when creating the Docker client. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, dockerClient, tfTypes.MapNull(tfTypes.StringType), &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *swarmNodeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state swarmNodeResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dockerClient, err := r.providerData.NewClient(state.Node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client in Read",
+			"An unexpected error occurred when creating the Docker client in Read. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	node, _, err := dockerClient.NodeInspectWithRaw(ctx, state.ID.ValueString())
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			// Node was removed from the swarm
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error Reading Node",
+			"Could not read node ID "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(flattenNodeSpec(ctx, node, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update applies the changed node spec.
+func (r *swarmNodeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state swarmNodeResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dockerClient, err := r.providerData.NewClient(plan.Node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client in Update",
+			"An unexpected error occurred when creating the Docker client in Update. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, dockerClient, state.Labels, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes the labels managed by the resource. Availability, role
+// and name are left as they are.
+func (r *swarmNodeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state swarmNodeResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state.Labels.IsNull() || len(state.Labels.Elements()) == 0 {
+		return
+	}
+
+	dockerClient, err := r.providerData.NewClient(state.Node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client in Delete",
+			"An unexpected error occurred when creating the Docker client in Delete. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	node, _, err := dockerClient.NodeInspectWithRaw(ctx, state.ID.ValueString())
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error Reading Node",
+			"Could not read node ID "+state.ID.ValueString()+" before removing its labels: "+err.Error(),
+		)
+		return
+	}
+
+	spec := node.Spec
+	for key := range state.Labels.Elements() {
+		delete(spec.Labels, key)
+	}
+	err = dockerClient.NodeUpdate(ctx, node.ID, node.Version, spec)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Updating Node",
+			"Could not remove the labels of node "+node.ID+", unexpected error: "+err.Error(),
+		)
+		return
+	}
+
+	tflog.Trace(ctx, "removed node labels", map[string]interface{}{
+		"node_id": node.ID,
+	})
+}
+
+// apply updates the node spec on top of the current one and refreshes the
+// model. The labels previously managed are replaced by the configured ones,
+// other labels of the node are left untouched.
+func (r *swarmNodeResource) apply(ctx context.Context, dockerClient *client.Client, prior tfTypes.Map, model *swarmNodeResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	node, _, err := dockerClient.NodeInspectWithRaw(ctx, model.NodeID.ValueString())
+	if err != nil {
+		diags.AddError(
+			"Error Reading Node",
+			"Could not read node "+model.NodeID.ValueString()+": "+err.Error(),
+		)
+		return diags
+	}
+
+	spec := node.Spec
+	if isKnown(model.Name) {
+		spec.Name = model.Name.ValueString()
+	}
+	if isKnown(model.Availability) {
+		spec.Availability = swarm.NodeAvailability(model.Availability.ValueString())
+	}
+	if isKnown(model.Role) {
+		spec.Role = swarm.NodeRole(model.Role.ValueString())
+	}
+	if node.Spec.Role == swarm.NodeRoleManager && spec.Role == swarm.NodeRoleWorker && !model.AllowQuorumLoss.ValueBool() {
+		diags.Append(checkManagerRemoval(ctx, dockerClient, node.ID, true)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
+	if isKnown(model.Labels) {
+		priorLabels := map[string]string{}
+		diags.Append(prior.ElementsAs(ctx, &priorLabels, false)...)
+		desiredLabels := map[string]string{}
+		diags.Append(model.Labels.ElementsAs(ctx, &desiredLabels, false)...)
+		if diags.HasError() {
+			return diags
+		}
+		spec.Labels, _ = mergeLabels(node.Spec.Labels, priorLabels, desiredLabels)
+	}
+
+	// The version index guards against concurrent changes of the node
+	err = dockerClient.NodeUpdate(ctx, node.ID, node.Version, spec)
+	if err != nil {
+		diags.AddError(
+			"Error Updating Node",
+			"Could not update node "+node.ID+", unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+	tflog.Trace(ctx, "updated node", map[string]interface{}{
+		"node_id": node.ID,
+		"version": node.Version.Index,
+	})
+
+	node, _, err = dockerClient.NodeInspectWithRaw(ctx, node.ID)
+	if err != nil {
+		diags.AddError(
+			"Error Reading Node",
+			"Could not read node "+node.ID+" after update: "+err.Error(),
+		)
+		return diags
+	}
+	diags.Append(flattenNodeSpec(ctx, node, model)...)
+	return diags
+}
+
+// flattenNodeSpec copies the node spec into the model, and the values of
+// the labels it manages.
+func flattenNodeSpec(ctx context.Context, node swarm.Node, model *swarmNodeResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	model.ID = tfTypes.StringValue(node.ID)
+	model.Hostname = tfTypes.StringValue(node.Description.Hostname)
+	model.Name = tfTypes.StringValue(node.Spec.Name)
+	model.Availability = tfTypes.StringValue(string(node.Spec.Availability))
+	model.Role = tfTypes.StringValue(string(node.Spec.Role))
+
+	// Only the labels managed by the resource are read back, labels set
+	// elsewhere on the node are not drift
+	if model.Labels.IsNull() {
+		return diags
+	}
+	labels := map[string]string{}
+	for key := range model.Labels.Elements() {
+		if value, ok := node.Spec.Labels[key]; ok {
+			labels[key] = value
+		}
+	}
+	model.Labels, diags = tfTypes.MapValueFrom(ctx, tfTypes.StringType, labels)
+	return diags
+}
@@ -0,0 +1,93 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwarmNodeResource_Metadata(t *testing.T) {
+	r := NewSwarmNodeResource()
+
+	req := resource.MetadataRequest{
+		ProviderTypeName: "swarm",
+	}
+	resp := &resource.MetadataResponse{}
+
+	r.Metadata(context.Background(), req, resp)
+
+	assert.Equal(t, "swarm_node", resp.TypeName)
+}
+
+func TestSwarmNodeResource_Schema(t *testing.T) {
+	r := NewSwarmNodeResource()
+
+	req := resource.SchemaRequest{}
+	resp := &resource.SchemaResponse{}
+
+	r.Schema(context.Background(), req, resp)
+
+	assert.NotNil(t, resp.Schema)
+	assert.Equal(t, "Manage the labels, availability and role of an existing Docker Swarm node.", resp.Schema.Description)
+
+	// Check required attributes exist
+	assert.Contains(t, resp.Schema.Attributes, "id")
+	assert.Contains(t, resp.Schema.Attributes, "node")
+	assert.Contains(t, resp.Schema.Attributes, "node_id")
+	assert.Contains(t, resp.Schema.Attributes, "hostname")
+	assert.Contains(t, resp.Schema.Attributes, "name")
+	assert.Contains(t, resp.Schema.Attributes, "labels")
+	assert.Contains(t, resp.Schema.Attributes, "availability")
+	assert.Contains(t, resp.Schema.Attributes, "role")
+	assert.Contains(t, resp.Schema.Attributes, "allow_quorum_loss")
+}
+
+func TestFlattenNodeSpec(t *testing.T) {
+	node := swarm.Node{
+		ID: "node-1",
+		Spec: swarm.NodeSpec{
+			Annotations:  swarm.Annotations{Name: "edge", Labels: map[string]string{"zone": "eu-west-1b", "disk": "ssd"}},
+			Role:         swarm.NodeRoleWorker,
+			Availability: swarm.NodeAvailabilityDrain,
+		},
+		Description: swarm.NodeDescription{Hostname: "worker-1"},
+	}
+	model := swarmNodeResourceModel{Labels: tfTypes.MapValueMust(tfTypes.StringType, map[string]attr.Value{
+		"zone": tfTypes.StringValue("eu-west-1a"),
+	})}
+
+	diags := flattenNodeSpec(context.Background(), node, &model)
+
+	assert.False(t, diags.HasError())
+	assert.Equal(t, "node-1", model.ID.ValueString())
+	assert.Equal(t, "worker-1", model.Hostname.ValueString())
+	assert.Equal(t, "edge", model.Name.ValueString())
+	assert.Equal(t, "drain", model.Availability.ValueString())
+	assert.Equal(t, "worker", model.Role.ValueString())
+	// The managed label is refreshed, the foreign disk label is ignored
+	assert.Equal(t, map[string]attr.Value{"zone": tfTypes.StringValue("eu-west-1b")}, model.Labels.Elements())
+
+	// Labels that are not configured are not read back
+	model.Labels = tfTypes.MapNull(tfTypes.StringType)
+	diags = flattenNodeSpec(context.Background(), node, &model)
+	assert.False(t, diags.HasError())
+	assert.True(t, model.Labels.IsNull())
+
+	// Managed labels removed from the node show up as drift
+	node.Spec.Labels = map[string]string{"disk": "ssd"}
+	model.Labels = tfTypes.MapValueMust(tfTypes.StringType, map[string]attr.Value{"zone": tfTypes.StringValue("eu-west-1a")})
+	diags = flattenNodeSpec(context.Background(), node, &model)
+	assert.False(t, diags.HasError())
+	assert.False(t, model.Labels.IsNull())
+	assert.Empty(t, model.Labels.Elements())
+}
+
+func TestSwarmNodeResource_InterfaceCompliance(t *testing.T) {
+	var _ resource.Resource = &swarmNodeResource{}
+	var _ resource.ResourceWithConfigure = &swarmNodeResource{}
+}
@@ -0,0 +1,216 @@
+package resources
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sntns/terraform-provider-swarm/internal/docker"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource              = &swarmUnlockResource{}
+	_ resource.ResourceWithConfigure = &swarmUnlockResource{}
+)
+
+// NewSwarmUnlockResource is a helper function to simplify the provider implementation.
+func NewSwarmUnlockResource() resource.Resource {
+	return &swarmUnlockResource{}
+}
+
+// swarmUnlockResource is the resource implementation.
+type swarmUnlockResource struct {
+	providerData *SwarmProviderData
+}
+
+// swarmUnlockResourceModel maps the resource schema data.
+type swarmUnlockResourceModel struct {
+	ID        tfTypes.String `tfsdk:"id"`
+	UnlockKey tfTypes.String `tfsdk:"unlock_key"`
+	NodeState tfTypes.String `tfsdk:"node_state"`
+	Node      *docker.TfNode `tfsdk:"node"`
+}
+
+// Metadata returns the resource type name.
+func (r *swarmUnlockResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_unlock"
+}
+
+// Schema defines the schema for the resource.
+func (r *swarmUnlockResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Unlock a locked manager of a Docker Swarm cluster with autolock enabled.",
+		Attributes: map[string]schema.Attribute{
+			"node": docker.NodeSchema,
+			"id": schema.StringAttribute{
+				Description: "ID of the unlocked node",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"unlock_key": schema.StringAttribute{
+				Description: "Unlock key of the swarm",
+				Required:    true,
+				Sensitive:   true,
+			},
+			"node_state": schema.StringAttribute{
+				Description: "Local swarm state of the node after unlocking",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Configure keeps the provider default connection, used when node is omitted.
+func (r *swarmUnlockResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	providerData, diags := ConfiguredProviderData(req.ProviderData)
+	resp.Diagnostics.Append(diags...)
+	r.providerData = providerData
+}
+
+// Create unlocks the node and sets the initial Terraform state.
+func (r *swarmUnlockResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan swarmUnlockResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dockerClient, err := r.providerData.NewClient(plan.Node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client",
+			"An unexpected error occurred when creating the Docker client. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(r.unlock(ctx, dockerClient, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *swarmUnlockResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state swarmUnlockResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dockerClient, err := r.providerData.NewClient(state.Node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client in Read",
+			"An unexpected error occurred when creating the Docker client in Read. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	nodeInfo, err := dockerClient.Info(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error Reading Node Info",
+			"Could not read node info: "+err.Error(),
+		)
+		return
+	}
+
+	// A manager locked again (e.g. after a restart) must be unlocked on the next apply
+	if nodeInfo.Swarm.LocalNodeState == swarm.LocalNodeStateLocked {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	state.NodeState = tfTypes.StringValue(string(nodeInfo.Swarm.LocalNodeState))
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update unlocks the node again with the new key if it is locked.
+func (r *swarmUnlockResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan swarmUnlockResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	dockerClient, err := r.providerData.NewClient(plan.Node)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Docker Client in Update",
+			"An unexpected error occurred when creating the Docker client in Update. \n\nDocker Client Error: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(r.unlock(ctx, dockerClient, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes the resource from the Terraform state. The node is left unlocked.
+func (r *swarmUnlockResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+// unlock unlocks the node if it is locked and fills the computed attributes.
+func (r *swarmUnlockResource) unlock(ctx context.Context, dockerClient *client.Client, model *swarmUnlockResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	nodeInfo, err := dockerClient.Info(ctx)
+	if err != nil {
+		diags.AddError(
+			"Error getting node info",
+			"Could not get node info before unlocking, unexpected error: "+err.Error(),
+		)
+		return diags
+	}
+
+	if nodeInfo.Swarm.LocalNodeState == swarm.LocalNodeStateLocked {
+		err = dockerClient.SwarmUnlock(ctx, swarm.UnlockRequest{UnlockKey: model.UnlockKey.ValueString()})
+		if err != nil {
+			diags.AddError(
+				"Error unlocking swarm",
+				"Could not unlock the swarm manager, unexpected error: "+err.Error(),
+			)
+			return diags
+		}
+		tflog.Trace(ctx, "unlocked swarm manager")
+
+		nodeInfo, err = dockerClient.Info(ctx)
+		if err != nil {
+			diags.AddError(
+				"Error getting node info",
+				"Could not get node info after unlocking, unexpected error: "+err.Error(),
+			)
+			return diags
+		}
+	}
+
+	model.ID = tfTypes.StringValue(nodeInfo.Swarm.NodeID)
+	model.NodeState = tfTypes.StringValue(string(nodeInfo.Swarm.LocalNodeState))
+	return diags
+}
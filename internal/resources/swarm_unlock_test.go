@@ -0,0 +1,49 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwarmUnlockResource_Metadata(t *testing.T) {
+	r := NewSwarmUnlockResource()
+
+	req := resource.MetadataRequest{
+		ProviderTypeName: "swarm",
+	}
+	resp := &resource.MetadataResponse{}
+
+	r.Metadata(context.Background(), req, resp)
+
+	assert.Equal(t, "swarm_unlock", resp.TypeName)
+}
+
+func TestSwarmUnlockResource_Schema(t *testing.T) {
+	r := NewSwarmUnlockResource()
+
+	req := resource.SchemaRequest{}
+	resp := &resource.SchemaResponse{}
+
+	r.Schema(context.Background(), req, resp)
+
+	assert.NotNil(t, resp.Schema)
+	assert.Equal(t, "Unlock a locked manager of a Docker Swarm cluster with autolock enabled.", resp.Schema.Description)
+
+	// Check required attributes exist
+	assert.Contains(t, resp.Schema.Attributes, "id")
+	assert.Contains(t, resp.Schema.Attributes, "node")
+	assert.Contains(t, resp.Schema.Attributes, "unlock_key")
+	assert.Contains(t, resp.Schema.Attributes, "node_state")
+
+	// Verify sensitive attributes
+	unlockKey := resp.Schema.Attributes["unlock_key"]
+	assert.True(t, unlockKey.(interface{ IsSensitive() bool }).IsSensitive())
+}
+
+func TestSwarmUnlockResource_InterfaceCompliance(t *testing.T) {
+	var _ resource.Resource = &swarmUnlockResource{}
+	var _ resource.ResourceWithConfigure = &swarmUnlockResource{}
+}
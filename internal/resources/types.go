@@ -1,26 +1,106 @@
 package resources
 
 import (
+	"fmt"
+
+	"github.com/docker/docker/client"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sntns/terraform-provider-swarm/internal/docker"
 )
 
 // DockerClientConfig represents the Docker client configuration
 type DockerClientConfig struct {
 	Host       string
+	Context    string
 	CertPath   string
 	KeyPath    string
 	CaPath     string
 	APIVersion string
 }
 
+// dockerConfig returns the connection settings as a docker.Config.
+func (c *DockerClientConfig) dockerConfig() docker.Config {
+	return docker.Config{
+		Host:       c.Host,
+		Context:    c.Context,
+		CertPath:   c.CertPath,
+		KeyPath:    c.KeyPath,
+		CaPath:     c.CaPath,
+		APIVersion: c.APIVersion,
+	}
+}
+
+// defaultNodeConfig is the key of the provider-level connection in NodeConfigs.
+const defaultNodeConfig = "default"
+
 // SwarmProviderData holds comprehensive provider configuration
 type SwarmProviderData struct {
 	NodeConfigs map[string]*DockerClientConfig
+	// RegistryAuth is the encoded registry_auth sent with service requests
+	RegistryAuth string
+	// Clients shares the Docker clients between resources, one per
+	// connection. Clients are not pooled when it is nil.
+	Clients *docker.Pool
+}
+
+// NewClient returns a Docker client for the node block, or for the default
+// connection of the provider when the block is omitted. The API version
+// pinned on the provider applies to the node blocks too. The provider data
+// may be nil when the provider is not configured. Clients taken from the
+// pool of the provider must not be closed.
+func (d *SwarmProviderData) NewClient(node *docker.TfNode) (*client.Client, error) {
+	var defaultConfig *DockerClientConfig
+	if d != nil {
+		defaultConfig = d.NodeConfigs[defaultNodeConfig]
+	}
+
+	if node == nil {
+		if defaultConfig == nil {
+			return nil, fmt.Errorf("node connection is not configured and the provider has no default connection")
+		}
+		return d.client(defaultConfig.dockerConfig())
+	}
+
+	dockerConfig := docker.ExtractConfig(*node)
+	if defaultConfig != nil {
+		dockerConfig.APIVersion = defaultConfig.APIVersion
+	}
+	return d.client(dockerConfig)
+}
+
+// client returns the client of the connection from the pool of the
+// provider, or a new client when there is no pool.
+func (d *SwarmProviderData) client(dockerConfig docker.Config) (*client.Client, error) {
+	if d == nil || d.Clients == nil {
+		return dockerConfig.NewClient()
+	}
+	return d.Clients.Client(dockerConfig)
+}
+
+// ConfiguredProviderData returns the provider data handed to the Configure
+// method of resources and data sources, nil before the provider is configured.
+func ConfiguredProviderData(providerData any) (*SwarmProviderData, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if providerData == nil {
+		return nil, diags
+	}
+	data, ok := providerData.(*SwarmProviderData)
+	if !ok {
+		diags.AddError(
+			"Unexpected Provider Data Type",
+			fmt.Sprintf("Expected *resources.SwarmProviderData, got: %T. Please report this issue to the provider developers.", providerData),
+		)
+		return nil, diags
+	}
+	return data, diags
 }
 
 // SwarmProviderModel represents the provider configuration schema
 type SwarmProviderModel struct {
 	Host         types.String `tfsdk:"host"`
+	Context      types.String `tfsdk:"context"`
 	CertPath     types.String `tfsdk:"cert_path"`
 	KeyPath      types.String `tfsdk:"key_path"`
 	CaPath       types.String `tfsdk:"ca_path"`
@@ -0,0 +1,73 @@
+package resources
+
+import (
+	"testing"
+
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sntns/terraform-provider-swarm/internal/docker"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwarmProviderData_NewClient(t *testing.T) {
+	providerData := &SwarmProviderData{
+		NodeConfigs: map[string]*DockerClientConfig{
+			"default": {Host: "tcp://localhost:2376", APIVersion: "1.41"},
+		},
+	}
+
+	// Without node block, the provider connection is used
+	dockerClient, err := providerData.NewClient(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://localhost:2376", dockerClient.DaemonHost())
+	assert.Equal(t, "1.41", dockerClient.ClientVersion())
+
+	// The node block wins, with the API version of the provider
+	node := &docker.TfNode{Host: tfTypes.StringValue("tcp://192.168.1.100:2376"), SSHOpts: tfTypes.ListNull(tfTypes.StringType)}
+	dockerClient, err = providerData.NewClient(node)
+	assert.NoError(t, err)
+	assert.Equal(t, "tcp://192.168.1.100:2376", dockerClient.DaemonHost())
+	assert.Equal(t, "1.41", dockerClient.ClientVersion())
+
+	// An unconfigured provider needs the node block
+	var unconfigured *SwarmProviderData
+	_, err = unconfigured.NewClient(nil)
+	assert.Error(t, err)
+	_, err = unconfigured.NewClient(node)
+	assert.NoError(t, err)
+}
+
+func TestSwarmProviderData_NewClientPool(t *testing.T) {
+	providerData := &SwarmProviderData{
+		NodeConfigs: map[string]*DockerClientConfig{
+			"default": {Host: "tcp://localhost:2376"},
+		},
+		Clients: docker.NewPool(),
+	}
+	defer providerData.Clients.Close()
+
+	// The connections are shared between resources
+	first, err := providerData.NewClient(nil)
+	assert.NoError(t, err)
+	again, err := providerData.NewClient(nil)
+	assert.NoError(t, err)
+	assert.Same(t, first, again)
+
+	node := &docker.TfNode{Host: tfTypes.StringValue("tcp://192.168.1.100:2376"), SSHOpts: tfTypes.ListNull(tfTypes.StringType)}
+	nodeClient, err := providerData.NewClient(node)
+	assert.NoError(t, err)
+	assert.NotSame(t, first, nodeClient)
+}
+
+func TestConfiguredProviderData(t *testing.T) {
+	providerData, diags := ConfiguredProviderData(nil)
+	assert.Nil(t, providerData)
+	assert.False(t, diags.HasError())
+
+	expected := &SwarmProviderData{}
+	providerData, diags = ConfiguredProviderData(expected)
+	assert.Same(t, expected, providerData)
+	assert.False(t, diags.HasError())
+
+	_, diags = ConfiguredProviderData("unexpected")
+	assert.True(t, diags.HasError())
+}
@@ -0,0 +1,98 @@
+package resources
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// durationValidator checks that a string attribute is a Go duration (e.g. "30s", "5m").
+type durationValidator struct{}
+
+var _ validator.String = durationValidator{}
+
+// Description returns a plain text description of the validator's behavior.
+func (v durationValidator) Description(_ context.Context) string {
+	return "value must be a duration such as 30s or 5m"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior.
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v durationValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	duration, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil || duration < 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			"Value "+req.ConfigValue.ValueString()+" is not a valid duration, use a value such as 30s or 5m.",
+		)
+	}
+}
+
+// cidrValidator checks that a string attribute is a network in CIDR notation (e.g. "10.20.0.0/16").
+type cidrValidator struct{}
+
+var _ validator.String = cidrValidator{}
+
+// Description returns a plain text description of the validator's behavior.
+func (v cidrValidator) Description(_ context.Context) string {
+	return "value must be a network in CIDR notation such as 10.20.0.0/16"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior.
+func (v cidrValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v cidrValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, _, err := net.ParseCIDR(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid CIDR",
+			"Value "+req.ConfigValue.ValueString()+" is not a network in CIDR notation, use a value such as 10.20.0.0/16.",
+		)
+	}
+}
+
+// pemValidator checks that a string attribute holds PEM-encoded material.
+type pemValidator struct{}
+
+var _ validator.String = pemValidator{}
+
+// Description returns a plain text description of the validator's behavior.
+func (v pemValidator) Description(_ context.Context) string {
+	return "value must be PEM-encoded"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior.
+func (v pemValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+// ValidateString performs the validation.
+func (v pemValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if block, _ := pem.Decode([]byte(req.ConfigValue.ValueString())); block == nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid PEM Material",
+			"Value is not PEM-encoded, it must start with a line such as -----BEGIN CERTIFICATE-----.",
+		)
+	}
+}
@@ -0,0 +1,72 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	tfTypes "github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDurationValidator(t *testing.T) {
+	cases := map[string]bool{
+		"30s":   false,
+		"5m":    false,
+		"1h30m": false,
+		"often": true,
+		"-1m":   true,
+	}
+	for value, expectError := range cases {
+		req := validator.StringRequest{
+			Path:        path.Root("drain_timeout"),
+			ConfigValue: tfTypes.StringValue(value),
+		}
+		resp := &validator.StringResponse{}
+
+		durationValidator{}.ValidateString(context.Background(), req, resp)
+
+		assert.Equal(t, expectError, resp.Diagnostics.HasError(), value)
+	}
+}
+
+func TestCIDRValidator(t *testing.T) {
+	cases := map[string]bool{
+		"10.20.0.0/16":  false,
+		"172.30.0.0/20": false,
+		"fd00::/64":     false,
+		"10.20.0.0":     true,
+		"10.20.0.0/33":  true,
+		"not-a-network": true,
+	}
+	for value, expectError := range cases {
+		req := validator.StringRequest{
+			Path:        path.Root("default_addr_pool"),
+			ConfigValue: tfTypes.StringValue(value),
+		}
+		resp := &validator.StringResponse{}
+
+		cidrValidator{}.ValidateString(context.Background(), req, resp)
+
+		assert.Equal(t, expectError, resp.Diagnostics.HasError(), value)
+	}
+}
+
+func TestPEMValidator(t *testing.T) {
+	cases := map[string]bool{
+		"-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n": false,
+		"MIIB": true,
+	}
+	for value, expectError := range cases {
+		req := validator.StringRequest{
+			Path:        path.Root("signing_ca_cert"),
+			ConfigValue: tfTypes.StringValue(value),
+		}
+		resp := &validator.StringResponse{}
+
+		pemValidator{}.ValidateString(context.Background(), req, resp)
+
+		assert.Equal(t, expectError, resp.Diagnostics.HasError(), value)
+	}
+}
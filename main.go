@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/sntns/terraform-provider-swarm/internal/docker"
 	"github.com/sntns/terraform-provider-swarm/internal/provider"
 )
 
@@ -21,7 +22,11 @@ func main() {
 		Debug:   debug,
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New, opts)
+	// The Docker clients are shared by the resources and closed once
+	// Terraform is done with the provider
+	clients := docker.NewPool()
+	err := providerserver.Serve(context.Background(), provider.NewWithClients(clients), opts)
+	clients.Close()
 
 	if err != nil {
 		log.Fatal(err.Error())